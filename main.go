@@ -14,6 +14,7 @@ import (
 	"github.com/pocketbase/pocketbase/plugins/jsvm"
 	"github.com/pocketbase/pocketbase/plugins/migratecmd"
 	"github.com/pocketbase/pocketbase/tools/template"
+	"github.com/spf13/cobra"
 
 	"venvi/providers"
 	"venvi/routes"
@@ -32,6 +33,16 @@ func main() {
 		Automigrate: true, // auto run migrations on serve
 	})
 
+	// Identify ourselves to the sources
+	app.RootCmd.PersistentFlags().StringVar(&providers.UserAgent, "user-agent", providers.UserAgent, "User-Agent sent to event sources")
+
+	// Load additional sources (HTML scrapers, JSON APIs) declared as JSON definitions
+	sources, err := providers.LoadSourceDefinitions("./pb_sources")
+	if err != nil {
+		log.Fatal(err)
+	}
+	providers.Providers = append(providers.Providers, sources...)
+
 	// Register routes and jobs on serve
 	app.OnServe().BindFunc(func(se *core.ServeEvent) error {
 		// Serve static files from pb_public
@@ -46,17 +57,45 @@ func main() {
 		return se.Next()
 	})
 
-	// Register scheduled job for event sync
-	app.Cron().MustAdd("sync_events", "0 */6 * * *", func() {
-		log.Println("Running scheduled event sync...")
-		stats, err := providers.SyncAllEvents(app)
-		if err != nil {
-			log.Printf("Sync failed: %v", err)
-			return
+	// Register one scheduled sync job per provider
+	app.OnServe().BindFunc(func(se *core.ServeEvent) error {
+		if err := providers.RegisterSyncJobs(app); err != nil {
+			log.Printf("Failed to register sync jobs: %v", err)
 		}
-		log.Printf("Sync complete: %v", stats)
+		return se.Next()
 	})
 
+	// Re-register the jobs when a provider is toggled or rescheduled
+	refreshSyncJobs := func(e *core.RecordEvent) error {
+		if err := providers.RegisterSyncJobs(app); err != nil {
+			log.Printf("Failed to refresh sync jobs: %v", err)
+		}
+		return e.Next()
+	}
+	app.OnRecordAfterCreateSuccess(providers.ProvidersCollection).BindFunc(refreshSyncJobs)
+	app.OnRecordAfterUpdateSuccess(providers.ProvidersCollection).BindFunc(refreshSyncJobs)
+	app.OnRecordAfterDeleteSuccess(providers.ProvidersCollection).BindFunc(refreshSyncJobs)
+
+	// Re-run mapping over the archived raw payloads, without fetching
+	var remapProvider string
+	remapCmd := &cobra.Command{
+		Use:   "remap",
+		Short: "Re-map the latest archived raw events of the providers",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			stats, err := providers.RemapEvents(app, remapProvider)
+			if err != nil {
+				return err
+			}
+			for _, s := range stats {
+				log.Printf("Remapped %s: %d mapped, %d new, %d updated, %d errors",
+					s.Provider, s.Mapped, s.New, s.Updated, s.Errors)
+			}
+			return nil
+		},
+	}
+	remapCmd.Flags().StringVar(&remapProvider, "provider", "", "only remap this provider (source name)")
+	app.RootCmd.AddCommand(remapCmd)
+
 	// Custom admin dashboard message
 	app.OnServe().BindFunc(func(se *core.ServeEvent) error {
 		se.Router.GET("/api/venvi/health", func(e *core.RequestEvent) error {
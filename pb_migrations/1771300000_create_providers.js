@@ -0,0 +1,81 @@
+/// <reference path="../pb_data/types.d.ts" />
+
+// Migration: Create providers collection holding the runtime configuration
+// of event sources. Records override the compiled-in providers by source_name.
+migrate((app) => {
+    const collection = new Collection({
+        "name": "providers",
+        "type": "base",
+        "fields": [
+            {
+                "name": "type",
+                "type": "select",
+                "required": true,
+                "maxSelect": 1,
+                "values": [
+                    "odh",
+                    "euro_hackathons",
+                    "drinbz",
+                    "noi",
+                    "unibz",
+                    "museion",
+                    "ics",
+                    "feed",
+                    "schemaorg",
+                    "html",
+                    "json_api",
+                    "tribe_events"
+                ]
+            },
+            {
+                "name": "source_name",
+                "type": "text",
+                "required": true
+            },
+            {
+                "name": "enabled",
+                "type": "bool",
+                "required": false
+            },
+            {
+                "name": "base_url",
+                "type": "url",
+                "required": false
+            },
+            {
+                "name": "options",
+                "type": "json",
+                "required": false
+            },
+            {
+                "name": "schedule",
+                "type": "text",
+                "required": false
+            }
+        ],
+        "indexes": [
+            "CREATE UNIQUE INDEX idx_providers_source_name ON providers (source_name)"
+        ],
+        // Superusers only
+        "listRule": null,
+        "viewRule": null,
+        "createRule": null,
+        "updateRule": null,
+        "deleteRule": null
+    });
+
+    app.save(collection);
+
+    // Seed the built-in sources so they can be toggled from the dashboard
+    const builtin = ["odh", "euro_hackathons", "drinbz", "noi", "unibz", "museion"];
+    for (const name of builtin) {
+        const record = new Record(collection);
+        record.set("type", name);
+        record.set("source_name", name);
+        record.set("enabled", true);
+        app.save(record);
+    }
+}, (app) => {
+    const collection = app.findCollectionByNameOrId("providers");
+    return app.delete(collection);
+})
@@ -0,0 +1,19 @@
+/// <reference path="../pb_data/types.d.ts" />
+
+// Migration: Per-provider sync timeout in seconds (empty uses the default)
+migrate((app) => {
+    const providers = app.findCollectionByNameOrId("providers");
+
+    providers.fields.add(new NumberField({
+        "name": "timeout",
+        "required": false,
+        "min": 0,
+        "onlyInt": true
+    }));
+
+    app.save(providers);
+}, (app) => {
+    const providers = app.findCollectionByNameOrId("providers");
+    providers.fields.removeByName("timeout");
+    app.save(providers);
+})
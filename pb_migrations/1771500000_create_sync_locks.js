@@ -0,0 +1,51 @@
+/// <reference path="../pb_data/types.d.ts" />
+
+// Migration: Create sync_locks collection. A record named "sync" is held
+// while a sync runs, so that syncs never overlap, even across processes.
+migrate((app) => {
+    const collection = new Collection({
+        "name": "sync_locks",
+        "type": "base",
+        "fields": [
+            {
+                "name": "name",
+                "type": "text",
+                "required": true
+            },
+            {
+                "name": "holder",
+                "type": "text",
+                "required": false
+            },
+            {
+                "name": "job_id",
+                "type": "text",
+                "required": false
+            },
+            {
+                "name": "acquired_at",
+                "type": "date",
+                "required": false
+            },
+            {
+                "name": "heartbeat_at",
+                "type": "date",
+                "required": false
+            }
+        ],
+        "indexes": [
+            "CREATE UNIQUE INDEX idx_sync_locks_name ON sync_locks (name)"
+        ],
+        // Superusers only
+        "listRule": null,
+        "viewRule": null,
+        "createRule": null,
+        "updateRule": null,
+        "deleteRule": null
+    });
+
+    return app.save(collection);
+}, (app) => {
+    const collection = app.findCollectionByNameOrId("sync_locks");
+    return app.delete(collection);
+})
@@ -0,0 +1,96 @@
+/// <reference path="../pb_data/types.d.ts" />
+
+// Migration: Create sync_runs collection, one record per provider per sync
+migrate((app) => {
+    const collection = new Collection({
+        "name": "sync_runs",
+        "type": "base",
+        "fields": [
+            {
+                "name": "provider",
+                "type": "text",
+                "required": true
+            },
+            {
+                "name": "job_id",
+                "type": "text",
+                "required": false
+            },
+            {
+                // done, degraded, failed, timeout or cancelled
+                "name": "state",
+                "type": "text",
+                "required": true
+            },
+            {
+                "name": "started_at",
+                "type": "date",
+                "required": true
+            },
+            {
+                "name": "finished_at",
+                "type": "date",
+                "required": false
+            },
+            {
+                "name": "duration_ms",
+                "type": "number",
+                "required": false
+            },
+            {
+                "name": "fetched",
+                "type": "number",
+                "required": false
+            },
+            {
+                "name": "mapped",
+                "type": "number",
+                "required": false
+            },
+            {
+                "name": "skipped",
+                "type": "number",
+                "required": false
+            },
+            {
+                "name": "new",
+                "type": "number",
+                "required": false
+            },
+            {
+                "name": "updated",
+                "type": "number",
+                "required": false
+            },
+            {
+                "name": "errors",
+                "type": "number",
+                "required": false
+            },
+            {
+                "name": "timeouts",
+                "type": "number",
+                "required": false
+            },
+            {
+                "name": "error_messages",
+                "type": "json",
+                "required": false
+            }
+        ],
+        "indexes": [
+            "CREATE INDEX idx_sync_runs_provider ON sync_runs (provider, started_at)"
+        ],
+        // Superusers only
+        "listRule": null,
+        "viewRule": null,
+        "createRule": null,
+        "updateRule": null,
+        "deleteRule": null
+    });
+
+    return app.save(collection);
+}, (app) => {
+    const collection = app.findCollectionByNameOrId("sync_runs");
+    return app.delete(collection);
+})
@@ -0,0 +1,17 @@
+/// <reference path="../pb_data/types.d.ts" />
+
+// Migration: Reasons why the breakage detector marked a run degraded
+migrate((app) => {
+    const runs = app.findCollectionByNameOrId("sync_runs");
+
+    runs.fields.add(new JSONField({
+        "name": "warnings",
+        "required": false
+    }));
+
+    app.save(runs);
+}, (app) => {
+    const runs = app.findCollectionByNameOrId("sync_runs");
+    runs.fields.removeByName("warnings");
+    app.save(runs);
+})
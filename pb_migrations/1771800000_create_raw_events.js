@@ -0,0 +1,55 @@
+/// <reference path="../pb_data/types.d.ts" />
+
+// Migration: Create raw_events collection, archiving the gzipped raw payloads
+// of each provider fetch for remapping and auditing
+migrate((app) => {
+    const collection = new Collection({
+        "name": "raw_events",
+        "type": "base",
+        "fields": [
+            {
+                "name": "provider",
+                "type": "text",
+                "required": true
+            },
+            {
+                "name": "job_id",
+                "type": "text",
+                "required": false
+            },
+            {
+                "name": "fetched_at",
+                "type": "date",
+                "required": true
+            },
+            {
+                "name": "count",
+                "type": "number",
+                "required": false
+            },
+            {
+                // Gzipped JSON array of the raw events
+                "name": "payload",
+                "type": "file",
+                "required": true,
+                "maxSelect": 1,
+                "maxSize": 104857600,
+                "protected": true
+            }
+        ],
+        "indexes": [
+            "CREATE INDEX idx_raw_events_provider ON raw_events (provider, fetched_at)"
+        ],
+        // Superusers only
+        "listRule": null,
+        "viewRule": null,
+        "createRule": null,
+        "updateRule": null,
+        "deleteRule": null
+    });
+
+    return app.save(collection);
+}, (app) => {
+    const collection = app.findCollectionByNameOrId("raw_events");
+    return app.delete(collection);
+})
@@ -0,0 +1,67 @@
+/// <reference path="../pb_data/types.d.ts" />
+
+// Migration: Create http_cache collection for conditional provider requests,
+// and mark sync runs that were answered with 304 Not Modified
+migrate((app) => {
+    const collection = new Collection({
+        "name": "http_cache",
+        "type": "base",
+        "fields": [
+            {
+                "name": "url",
+                "type": "text",
+                "required": true
+            },
+            {
+                "name": "etag",
+                "type": "text",
+                "required": false
+            },
+            {
+                "name": "last_modified",
+                "type": "text",
+                "required": false
+            },
+            {
+                // Gzipped response body, served when the source answers 304
+                "name": "body",
+                "type": "file",
+                "required": true,
+                "maxSelect": 1,
+                "maxSize": 104857600,
+                "protected": true
+            },
+            {
+                "name": "stored_at",
+                "type": "date",
+                "required": false
+            }
+        ],
+        "indexes": [
+            "CREATE UNIQUE INDEX idx_http_cache_url ON http_cache (url)"
+        ],
+        // Superusers only
+        "listRule": null,
+        "viewRule": null,
+        "createRule": null,
+        "updateRule": null,
+        "deleteRule": null
+    });
+
+    app.save(collection);
+
+    const runs = app.findCollectionByNameOrId("sync_runs");
+    runs.fields.add(new BoolField({
+        "name": "not_modified",
+        "required": false
+    }));
+
+    app.save(runs);
+}, (app) => {
+    const runs = app.findCollectionByNameOrId("sync_runs");
+    runs.fields.removeByName("not_modified");
+    app.save(runs);
+
+    const collection = app.findCollectionByNameOrId("http_cache");
+    app.delete(collection);
+})
@@ -0,0 +1,17 @@
+/// <reference path="../pb_data/types.d.ts" />
+
+// Migration: Streamed fetches are archived page by page
+migrate((app) => {
+    const rawEvents = app.findCollectionByNameOrId("raw_events");
+
+    rawEvents.fields.add(new NumberField({
+        "name": "page",
+        "required": false
+    }));
+
+    app.save(rawEvents);
+}, (app) => {
+    const rawEvents = app.findCollectionByNameOrId("raw_events");
+    rawEvents.fields.removeByName("page");
+    app.save(rawEvents);
+})
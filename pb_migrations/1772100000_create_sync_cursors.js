@@ -0,0 +1,59 @@
+/// <reference path="../pb_data/types.d.ts" />
+
+// Migration: Create sync_cursors collection for incremental provider syncs,
+// and mark sync runs that only fetched changes
+migrate((app) => {
+    const collection = new Collection({
+        "name": "sync_cursors",
+        "type": "base",
+        "fields": [
+            {
+                "name": "provider",
+                "type": "text",
+                "required": true
+            },
+            {
+                // Opaque to the sync, interpreted by the provider
+                "name": "cursor",
+                "type": "text",
+                "required": false
+            },
+            {
+                "name": "synced_at",
+                "type": "date",
+                "required": false
+            },
+            {
+                "name": "full_sync_at",
+                "type": "date",
+                "required": false
+            }
+        ],
+        "indexes": [
+            "CREATE UNIQUE INDEX idx_sync_cursors_provider ON sync_cursors (provider)"
+        ],
+        // Superusers only
+        "listRule": null,
+        "viewRule": null,
+        "createRule": null,
+        "updateRule": null,
+        "deleteRule": null
+    });
+
+    app.save(collection);
+
+    const runs = app.findCollectionByNameOrId("sync_runs");
+    runs.fields.add(new BoolField({
+        "name": "incremental",
+        "required": false
+    }));
+
+    app.save(runs);
+}, (app) => {
+    const runs = app.findCollectionByNameOrId("sync_runs");
+    runs.fields.removeByName("incremental");
+    app.save(runs);
+
+    const collection = app.findCollectionByNameOrId("sync_cursors");
+    app.delete(collection);
+})
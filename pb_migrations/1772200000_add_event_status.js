@@ -0,0 +1,36 @@
+/// <reference path="../pb_data/types.d.ts" />
+
+// Migration: Track when events were last seen at their source, mark events
+// their source dropped as removed, and count them per sync run
+migrate((app) => {
+    const events = app.findCollectionByNameOrId("events");
+
+    // "active" or "removed"
+    events.fields.add(new TextField({
+        "name": "status",
+        "required": false
+    }));
+    events.fields.add(new DateField({
+        "name": "last_seen_at",
+        "required": false
+    }));
+
+    app.save(events);
+
+    const runs = app.findCollectionByNameOrId("sync_runs");
+    runs.fields.add(new NumberField({
+        "name": "removed",
+        "required": false
+    }));
+
+    app.save(runs);
+}, (app) => {
+    const runs = app.findCollectionByNameOrId("sync_runs");
+    runs.fields.removeByName("removed");
+    app.save(runs);
+
+    const events = app.findCollectionByNameOrId("events");
+    events.fields.removeByName("status");
+    events.fields.removeByName("last_seen_at");
+    app.save(events);
+})
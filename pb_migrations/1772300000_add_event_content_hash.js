@@ -0,0 +1,30 @@
+/// <reference path="../pb_data/types.d.ts" />
+
+// Migration: Store a content hash per event, so that unchanged events are not
+// written again, and count them per sync run
+migrate((app) => {
+    const events = app.findCollectionByNameOrId("events");
+
+    events.fields.add(new TextField({
+        "name": "content_hash",
+        "required": false
+    }));
+
+    app.save(events);
+
+    const runs = app.findCollectionByNameOrId("sync_runs");
+    runs.fields.add(new NumberField({
+        "name": "unchanged",
+        "required": false
+    }));
+
+    app.save(runs);
+}, (app) => {
+    const runs = app.findCollectionByNameOrId("sync_runs");
+    runs.fields.removeByName("unchanged");
+    app.save(runs);
+
+    const events = app.findCollectionByNameOrId("events");
+    events.fields.removeByName("content_hash");
+    app.save(events);
+})
@@ -0,0 +1,52 @@
+/// <reference path="../pb_data/types.d.ts" />
+
+// Migration: Create event_revisions collection recording the field-level
+// changes the sync makes to events
+migrate((app) => {
+    const events = app.findCollectionByNameOrId("events");
+
+    const collection = new Collection({
+        "name": "event_revisions",
+        "type": "base",
+        "fields": [
+            {
+                "name": "event",
+                "type": "relation",
+                "required": true,
+                "collectionId": events.id,
+                "cascadeDelete": true,
+                "maxSelect": 1
+            },
+            {
+                "name": "provider",
+                "type": "text",
+                "required": false
+            },
+            {
+                "name": "changed_at",
+                "type": "date",
+                "required": true
+            },
+            {
+                // {"field": {"old": ..., "new": ...}}
+                "name": "changes",
+                "type": "json",
+                "required": false
+            }
+        ],
+        "indexes": [
+            "CREATE INDEX idx_event_revisions_event ON event_revisions (event, changed_at)"
+        ],
+        // Superusers only, see /api/venvi/events/{id}/history
+        "listRule": null,
+        "viewRule": null,
+        "createRule": null,
+        "updateRule": null,
+        "deleteRule": null
+    });
+
+    app.save(collection);
+}, (app) => {
+    const collection = app.findCollectionByNameOrId("event_revisions");
+    app.delete(collection);
+})
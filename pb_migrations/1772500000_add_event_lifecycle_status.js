@@ -0,0 +1,13 @@
+/// <reference path="../pb_data/types.d.ts" />
+
+// Migration: Event status now holds the lifecycle status (scheduled,
+// rescheduled, postponed, cancelled) besides "removed"
+migrate((app) => {
+    app.db()
+        .newQuery("UPDATE events SET status = 'scheduled' WHERE status IN ('', 'active')")
+        .execute();
+}, (app) => {
+    app.db()
+        .newQuery("UPDATE events SET status = 'active' WHERE status != 'removed'")
+        .execute();
+})
@@ -0,0 +1,49 @@
+/// <reference path="../pb_data/types.d.ts" />
+
+// Migration: Replace the static is_new flag of events with first_seen_at, so
+// that newness can be computed per viewer, and track user visits
+migrate((app) => {
+    const events = app.findCollectionByNameOrId("events");
+
+    events.fields.add(new DateField({
+        "name": "first_seen_at",
+        "required": false
+    }));
+    events.fields.removeByName("is_new");
+
+    app.save(events);
+
+    // Events have no creation date, so those synced so far count as first
+    // seen now
+    app.db()
+        .newQuery("UPDATE events SET first_seen_at = {:now} WHERE first_seen_at = '' OR first_seen_at IS NULL")
+        .bind({ "now": new DateTime().string() })
+        .execute();
+
+    const users = app.findCollectionByNameOrId("users");
+
+    users.fields.add(new DateField({
+        "name": "last_active_at",
+        "required": false
+    }));
+    // End of the previous visit, events first seen since then are new
+    users.fields.add(new DateField({
+        "name": "previous_visit_at",
+        "required": false
+    }));
+
+    app.save(users);
+}, (app) => {
+    const users = app.findCollectionByNameOrId("users");
+    users.fields.removeByName("last_active_at");
+    users.fields.removeByName("previous_visit_at");
+    app.save(users);
+
+    const events = app.findCollectionByNameOrId("events");
+    events.fields.add(new BoolField({
+        "name": "is_new",
+        "required": false
+    }));
+    events.fields.removeByName("first_seen_at");
+    app.save(events);
+})
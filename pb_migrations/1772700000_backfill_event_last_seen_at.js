@@ -0,0 +1,12 @@
+/// <reference path="../pb_data/types.d.ts" />
+
+// Migration: Start the removal grace of events synced before last_seen_at was
+// recorded now, as events have no timestamp telling when they were last written
+migrate((app) => {
+    app.db()
+        .newQuery("UPDATE events SET last_seen_at = {:now} WHERE last_seen_at = '' OR last_seen_at IS NULL")
+        .bind({ "now": new DateTime().string() })
+        .execute();
+}, (app) => {
+    // Nothing to undo, the backfilled dates are as good as any
+})
@@ -0,0 +1,17 @@
+/// <reference path="../pb_data/types.d.ts" />
+
+// Migration: Flag archived incremental fetches, which remapping skips
+migrate((app) => {
+    const rawEvents = app.findCollectionByNameOrId("raw_events");
+
+    rawEvents.fields.add(new BoolField({
+        "name": "incremental",
+        "required": false
+    }));
+
+    app.save(rawEvents);
+}, (app) => {
+    const rawEvents = app.findCollectionByNameOrId("raw_events");
+    rawEvents.fields.removeByName("incremental");
+    app.save(rawEvents);
+})
@@ -0,0 +1,18 @@
+/// <reference path="../pb_data/types.d.ts" />
+
+// Migration: Flag runs whose fetch missed part of the source, e.g. a failed
+// feed or capped pagination, and which were therefore not reconciled
+migrate((app) => {
+    const runs = app.findCollectionByNameOrId("sync_runs");
+
+    runs.fields.add(new BoolField({
+        "name": "partial",
+        "required": false
+    }));
+
+    app.save(runs);
+}, (app) => {
+    const runs = app.findCollectionByNameOrId("sync_runs");
+    runs.fields.removeByName("partial");
+    app.save(runs);
+})
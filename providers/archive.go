@@ -0,0 +1,256 @@
+package providers
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"time"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/filesystem"
+)
+
+// RawEventsCollection is the PocketBase collection archiving the raw payloads
+// of every fetch, so that events can be re-mapped without network access.
+const RawEventsCollection = "raw_events"
+
+// RawArchiveRetention is the number of archived fetches kept per provider.
+var RawArchiveRetention = 10
+
+// archiveRawEvents stores the raw events of a fetch as a gzipped JSON file.
+// Streamed fetches are archived in several records, numbered by page from 1.
+// incremental marks fetches holding only the changes since the previous
+// sync. Failures are logged, as they must not fail the sync itself. Nothing
+// is archived if the collection does not exist.
+func archiveRawEvents(app core.App, provider, jobID string, fetchedAt time.Time, page int, incremental bool, rawEvents []RawEvent) {
+	collection, err := app.FindCollectionByNameOrId(RawEventsCollection)
+	if err != nil {
+		return
+	}
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if err := json.NewEncoder(zw).Encode(rawEvents); err != nil {
+		log.Printf("Failed to archive raw events of %s: %v", provider, err)
+		return
+	}
+	if err := zw.Close(); err != nil {
+		log.Printf("Failed to archive raw events of %s: %v", provider, err)
+		return
+	}
+
+	file, err := filesystem.NewFileFromBytes(buf.Bytes(), provider+".json.gz")
+	if err != nil {
+		log.Printf("Failed to archive raw events of %s: %v", provider, err)
+		return
+	}
+
+	record := core.NewRecord(collection)
+	record.Set("provider", provider)
+	record.Set("job_id", jobID)
+	record.Set("fetched_at", fetchedAt)
+	record.Set("count", len(rawEvents))
+	record.Set("page", page)
+	record.Set("incremental", incremental)
+	record.Set("payload", file)
+	if err := app.Save(record); err != nil {
+		log.Printf("Failed to archive raw events of %s: %v", provider, err)
+		return
+	}
+
+	if page <= 1 {
+		pruneRawEvents(app, collection, provider)
+	}
+}
+
+// pruneRawEvents deletes all but the latest RawArchiveRetention fetches. The
+// latest full fetch is always kept, as remapping needs it.
+func pruneRawEvents(app core.App, collection *core.Collection, provider string) {
+	// The first page of each fetch marks where it starts
+	firsts, err := app.FindRecordsByFilter(
+		collection,
+		"provider = {:provider} && page <= 1",
+		"-fetched_at",
+		1,
+		RawArchiveRetention-1,
+		map[string]any{"provider": provider},
+	)
+	if err != nil || len(firsts) == 0 {
+		if err != nil {
+			log.Printf("Failed to prune raw events of %s: %v", provider, err)
+		}
+		return
+	}
+
+	full, err := latestFullFetch(app, provider)
+	if err != nil {
+		log.Printf("Failed to prune raw events of %s: %v", provider, err)
+		return
+	}
+	keep := firsts[0].GetDateTime("fetched_at")
+	if full != nil {
+		keep = full.GetDateTime("fetched_at")
+	}
+
+	old, err := app.FindRecordsByFilter(
+		collection,
+		"provider = {:provider} && fetched_at < {:cutoff} && fetched_at != {:keep}",
+		"",
+		0,
+		0,
+		map[string]any{"provider": provider, "cutoff": firsts[0].GetDateTime("fetched_at"), "keep": keep},
+	)
+	if err != nil {
+		log.Printf("Failed to prune raw events of %s: %v", provider, err)
+		return
+	}
+	for _, record := range old {
+		if err := app.Delete(record); err != nil {
+			log.Printf("Failed to prune raw events of %s: %v", provider, err)
+		}
+	}
+}
+
+// latestFullFetch returns the first archived page of the latest full fetch
+// of a provider, or nil if there is none.
+func latestFullFetch(app core.App, provider string) (*core.Record, error) {
+	latest, err := app.FindRecordsByFilter(
+		RawEventsCollection,
+		"provider = {:provider} && incremental != true",
+		"-fetched_at,page",
+		1,
+		0,
+		map[string]any{"provider": provider},
+	)
+	if err != nil || len(latest) == 0 {
+		return nil, err
+	}
+	return latest[0], nil
+}
+
+// LatestRawEvents returns the most recently archived full fetch of a
+// provider. Incremental fetches only hold what changed, so they are skipped.
+func LatestRawEvents(app core.App, provider string) ([]RawEvent, time.Time, error) {
+	latest, err := latestFullFetch(app, provider)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("finding raw events of %s: %w", provider, err)
+	}
+	if latest == nil {
+		return nil, time.Time{}, fmt.Errorf("no full fetch archived for %s", provider)
+	}
+
+	// A streamed fetch is archived page by page
+	pages, err := app.FindRecordsByFilter(
+		RawEventsCollection,
+		"provider = {:provider} && job_id = {:job_id} && fetched_at = {:fetched_at}",
+		"page",
+		0,
+		0,
+		map[string]any{
+			"provider":   provider,
+			"job_id":     latest.GetString("job_id"),
+			"fetched_at": latest.GetDateTime("fetched_at"),
+		},
+	)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("finding raw events of %s: %w", provider, err)
+	}
+
+	fsys, err := app.NewFilesystem()
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("opening filesystem: %w", err)
+	}
+	defer func() { _ = fsys.Close() }()
+
+	var rawEvents []RawEvent
+	for _, record := range pages {
+		pageEvents, err := readRawEvents(fsys, record)
+		if err != nil {
+			return nil, time.Time{}, fmt.Errorf("reading raw events of %s: %w", provider, err)
+		}
+		rawEvents = append(rawEvents, pageEvents...)
+	}
+	return rawEvents, latest.GetDateTime("fetched_at").Time(), nil
+}
+
+// readRawEvents reads the payload of an archive record.
+func readRawEvents(fsys *filesystem.System, record *core.Record) ([]RawEvent, error) {
+	r, err := fsys.GetReader(record.BaseFilesPath() + "/" + record.GetString("payload"))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = r.Close() }()
+
+	return decodeRawEvents(r)
+}
+
+// decodeRawEvents reads a gzipped JSON array of raw events.
+func decodeRawEvents(r io.Reader) ([]RawEvent, error) {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = zr.Close() }()
+
+	var rawEvents []RawEvent
+	if err := json.NewDecoder(zr).Decode(&rawEvents); err != nil {
+		return nil, err
+	}
+	return rawEvents, nil
+}
+
+// RemapEvents re-runs the mapping and upsert of the given provider, or of all
+// active providers if provider is empty, over their latest fully archived
+// fetches.
+// It makes no network requests. Like a sync, it holds the sync lock.
+func RemapEvents(app core.App, provider string) (map[string]SyncStats, error) {
+	active, err := loadActiveProviders(app)
+	if err != nil {
+		return nil, err
+	}
+	if provider != "" {
+		var selected []activeProvider
+		for _, p := range active {
+			if p.SourceName() == provider {
+				selected = append(selected, p)
+			}
+		}
+		if len(selected) == 0 {
+			return nil, fmt.Errorf("provider %s is not active", provider)
+		}
+		active = selected
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	job := newSyncJob(active, cancel)
+
+	if err := acquireSyncLock(app, job.ID); err != nil {
+		return nil, err
+	}
+	defer releaseSyncLock(app, job.ID)
+
+	stats := make(map[string]SyncStats, len(active))
+	for _, p := range active {
+		rawEvents, fetchedAt, err := LatestRawEvents(app, p.SourceName())
+		if err != nil {
+			log.Printf("Skipping remap of %s: %v", p.SourceName(), err)
+			s := SyncStats{Provider: p.SourceName()}
+			s.addError(err)
+			stats[p.SourceName()] = s
+			continue
+		}
+		log.Printf("Remapping %d raw events of %s fetched at %s", len(rawEvents), p.SourceName(), fetchedAt.Format(time.RFC3339))
+
+		s, err := writeEvents(ctx, app, p, rawEvents, fetchedAt, SyncStats{Provider: p.SourceName(), Fetched: len(rawEvents)}, job)
+		if err != nil {
+			s.addError(err)
+		}
+		stats[p.SourceName()] = s
+	}
+	return stats, nil
+}
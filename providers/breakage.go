@@ -0,0 +1,202 @@
+package providers
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/pocketbase/pocketbase/core"
+)
+
+// Breakage detection compares the yield of a sync with the provider's recent
+// healthy runs. Scrapers rarely fail loudly when a site changes: they return
+// nothing, or items without the fields they used to find.
+var (
+	// BaselineRuns is the number of recent healthy runs forming the baseline.
+	BaselineRuns = 10
+	// MinBaselineRuns is the number of healthy runs needed before judging,
+	// so that new providers are not flagged.
+	MinBaselineRuns = 3
+	// DropRatio flags runs mapping fewer events than this share of the baseline.
+	DropRatio = 0.5
+	// MinDropBaseline is the baseline size below which drops are not flagged,
+	// as small sources naturally fluctuate.
+	MinDropBaseline = 5.0
+	// SkipRatioIncrease flags runs whose share of skipped items (items without
+	// a usable title or date) exceeds the baseline share by this much.
+	SkipRatioIncrease = 0.3
+	// RelearnRuns is the number of consecutive degraded runs with similar
+	// yields after which their level becomes the baseline, so that a source
+	// that shrank for good is not reported as broken forever.
+	RelearnRuns = 3
+	// RelearnTolerance is how far the mapped counts of those runs may stray
+	// from their median to count as similar.
+	RelearnTolerance = 0.2
+)
+
+// syncBaseline is the typical yield of a provider.
+type syncBaseline struct {
+	// Runs is the number of healthy runs the baseline is computed from.
+	Runs int
+	// Mapped is the median number of mapped events.
+	Mapped float64
+	// SkipRatio is the mean share of skipped raw items.
+	SkipRatio float64
+}
+
+// loadSyncBaseline computes the baseline of a provider from its latest
+// healthy full runs that wrote events. Once RelearnRuns consecutive runs
+// were degraded with similar yields, the source settled at a new level:
+// those runs and the healthy runs since form the baseline instead, until
+// BaselineRuns healthy runs at the new level follow them. It returns a
+// zero baseline if runs are not recorded.
+func loadSyncBaseline(app core.App, provider string) (syncBaseline, error) {
+	collection, err := app.FindCollectionByNameOrId(SyncRunsCollection)
+	if err != nil {
+		return syncBaseline{}, nil
+	}
+
+	const fullRuns = "provider = {:provider} && not_modified != true && incremental != true"
+	params := map[string]any{"provider": provider, "done": ProviderDone, "degraded": ProviderDegraded}
+
+	if RelearnRuns > 0 {
+		recent, err := app.FindRecordsByFilter(
+			collection,
+			fullRuns+" && (state = {:done} || state = {:degraded})",
+			"-started_at",
+			BaselineRuns+RelearnRuns,
+			0,
+			params,
+		)
+		if err != nil {
+			return syncBaseline{}, fmt.Errorf("loading baseline of %s: %w", provider, err)
+		}
+		if runs, ok := relearnedRuns(recent); ok {
+			return baselineOf(runs), nil
+		}
+	}
+
+	runs, err := app.FindRecordsByFilter(
+		collection,
+		fullRuns+" && state = {:done}",
+		"-started_at",
+		BaselineRuns,
+		0,
+		params,
+	)
+	if err != nil {
+		return syncBaseline{}, fmt.Errorf("loading baseline of %s: %w", provider, err)
+	}
+	return baselineOf(runs), nil
+}
+
+// relearnedRuns looks for RelearnRuns consecutive settled runs among recent
+// runs, newest first, within the latest BaselineRuns healthy ones. If found,
+// it returns them along with the healthy runs since.
+func relearnedRuns(recent []*core.Record) ([]*core.Record, bool) {
+	var healthy, streak []*core.Record
+	for _, run := range recent {
+		if run.GetString("state") != ProviderDegraded {
+			if len(healthy) == BaselineRuns {
+				break
+			}
+			healthy = append(healthy, run)
+			streak = nil
+			continue
+		}
+		streak = append(streak, run)
+		if len(streak) >= RelearnRuns {
+			if window := streak[len(streak)-RelearnRuns:]; settled(window) {
+				return append(healthy, window...), true
+			}
+		}
+	}
+	return nil, false
+}
+
+// baselineOf computes the baseline formed by runs.
+func baselineOf(runs []*core.Record) syncBaseline {
+	mapped := make([]float64, len(runs))
+	skipRatio := 0.0
+	for i, run := range runs {
+		mapped[i] = run.GetFloat("mapped")
+		if fetched := run.GetFloat("fetched"); fetched > 0 {
+			skipRatio += run.GetFloat("skipped") / fetched
+		}
+	}
+
+	b := syncBaseline{Runs: len(runs)}
+	if len(runs) > 0 {
+		b.Mapped = median(mapped)
+		b.SkipRatio = skipRatio / float64(len(runs))
+	}
+	return b
+}
+
+// settled reports whether runs were all degraded while mapping about the
+// same number of events, i.e. the source settled at a new level.
+func settled(runs []*core.Record) bool {
+	mapped := make([]float64, len(runs))
+	for i, run := range runs {
+		if run.GetString("state") != ProviderDegraded {
+			return false
+		}
+		mapped[i] = run.GetFloat("mapped")
+	}
+	return similar(mapped)
+}
+
+// similar reports whether values are all within RelearnTolerance of their
+// median. Values around zero never are: a source mapping nothing is more
+// likely broken than shrunk.
+func similar(values []float64) bool {
+	if len(values) == 0 {
+		return false
+	}
+	m := median(values)
+	if m <= 0 {
+		return false
+	}
+	for _, v := range values {
+		if v < m*(1-RelearnTolerance) || v > m*(1+RelearnTolerance) {
+			return false
+		}
+	}
+	return true
+}
+
+// detectBreakage returns the reasons why stats look like a broken source
+// compared to the baseline, or nil if the run looks normal.
+func detectBreakage(b syncBaseline, stats SyncStats) []string {
+	if b.Runs < MinBaselineRuns || b.Mapped == 0 {
+		return nil
+	}
+
+	var reasons []string
+	switch {
+	case stats.Fetched == 0:
+		reasons = append(reasons, fmt.Sprintf("fetched no items, usually maps %.0f events", b.Mapped))
+	case stats.Mapped == 0:
+		reasons = append(reasons, fmt.Sprintf("mapped no events from %d items, usually maps %.0f", stats.Fetched, b.Mapped))
+	case b.Mapped >= MinDropBaseline && float64(stats.Mapped) < b.Mapped*DropRatio:
+		reasons = append(reasons, fmt.Sprintf("mapped %d events, usually %.0f", stats.Mapped, b.Mapped))
+	}
+
+	if stats.Fetched > 0 {
+		skipRatio := float64(stats.Skipped) / float64(stats.Fetched)
+		if skipRatio > b.SkipRatio+SkipRatioIncrease {
+			reasons = append(reasons, fmt.Sprintf("skipped %.0f%% of items (missing title or date), usually %.0f%%", skipRatio*100, b.SkipRatio*100))
+		}
+	}
+	return reasons
+}
+
+// median returns the median of values, which must not be empty.
+func median(values []float64) float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
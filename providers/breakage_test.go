@@ -0,0 +1,52 @@
+package providers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetectBreakage(t *testing.T) {
+	baseline := syncBaseline{Runs: 5, Mapped: 40, SkipRatio: 0.1}
+
+	tests := []struct {
+		name     string
+		baseline syncBaseline
+		stats    SyncStats
+		broken   bool
+	}{
+		{"Normal run", baseline, SyncStats{Fetched: 42, Mapped: 38, Skipped: 4}, false},
+		{"Small fluctuation", baseline, SyncStats{Fetched: 25, Mapped: 24, Skipped: 1}, false},
+		{"Nothing fetched", baseline, SyncStats{}, true},
+		{"Nothing mapped", baseline, SyncStats{Fetched: 40, Skipped: 40}, true},
+		{"Big drop", baseline, SyncStats{Fetched: 10, Mapped: 10}, true},
+		{"Skipped spike", baseline, SyncStats{Fetched: 40, Mapped: 22, Skipped: 18}, true},
+		{"Too few baseline runs", syncBaseline{Runs: 2, Mapped: 40}, SyncStats{}, false},
+		{"Empty baseline", syncBaseline{Runs: 5}, SyncStats{}, false},
+		{"Small source drop", syncBaseline{Runs: 5, Mapped: 3}, SyncStats{Fetched: 1, Mapped: 1}, false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			reasons := detectBreakage(tc.baseline, tc.stats)
+			if tc.broken {
+				assert.NotEmpty(t, reasons)
+			} else {
+				assert.Empty(t, reasons)
+			}
+		})
+	}
+}
+
+func TestMedian(t *testing.T) {
+	assert.Equal(t, 3.0, median([]float64{5, 1, 3}))
+	assert.Equal(t, 2.5, median([]float64{4, 1, 3, 2}))
+}
+
+func TestSimilar(t *testing.T) {
+	assert.True(t, similar([]float64{8, 9, 10}))
+	assert.True(t, similar([]float64{4, 4, 4}))
+	assert.False(t, similar([]float64{4, 9, 10}), "one run far off")
+	assert.False(t, similar([]float64{0, 0, 0}), "an empty source is not a new level")
+	assert.False(t, similar(nil))
+}
@@ -0,0 +1,101 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/pocketbase/pocketbase/core"
+)
+
+// SyncCursorsCollection is the PocketBase collection storing the cursor of
+// every IncrementalProvider. Deleting a record forces a full sync.
+const SyncCursorsCollection = "sync_cursors"
+
+// FullSyncInterval is how often incremental providers fetch their whole
+// catalogue anyway, to pick up anything an incremental fetch missed.
+var FullSyncInterval = 24 * time.Hour
+
+// syncCursorKey is the context key of the cursor of a provider sync.
+type syncCursorKey struct{}
+
+// withSyncCursor passes cursor to an IncrementalProvider.
+func withSyncCursor(ctx context.Context, cursor string) context.Context {
+	if cursor == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, syncCursorKey{}, cursor)
+}
+
+// syncCursor returns the cursor an IncrementalProvider should fetch from,
+// or "" for a full fetch.
+func syncCursor(ctx context.Context) string {
+	cursor, _ := ctx.Value(syncCursorKey{}).(string)
+	return cursor
+}
+
+// incrementalCursor returns the cursor for the next sync of a provider, or
+// "" if the provider is not incremental or a full sync is due.
+func incrementalCursor(app core.App, provider EventProvider, full bool) string {
+	if _, ok := provider.(IncrementalProvider); !ok || full {
+		return ""
+	}
+	record, err := findSyncCursor(app, provider.SourceName())
+	if err != nil {
+		log.Printf("Loading sync cursor of %s failed, syncing in full: %v", provider.SourceName(), err)
+		return ""
+	}
+	if record == nil || time.Since(record.GetDateTime("full_sync_at").Time()) > FullSyncInterval {
+		return ""
+	}
+	return record.GetString("cursor")
+}
+
+// saveSyncCursor stores the cursor after a successful sync that started at
+// startedAt. Failures are logged; the next sync is then a full one.
+func saveSyncCursor(app core.App, provider EventProvider, startedAt time.Time, incremental bool) {
+	inc, ok := provider.(IncrementalProvider)
+	if !ok {
+		return
+	}
+	collection, err := app.FindCollectionByNameOrId(SyncCursorsCollection)
+	if err != nil {
+		return
+	}
+
+	record, err := findSyncCursor(app, provider.SourceName())
+	if err != nil {
+		log.Printf("Failed to save sync cursor of %s: %v", provider.SourceName(), err)
+		return
+	}
+	if record == nil {
+		record = core.NewRecord(collection)
+		record.Set("provider", provider.SourceName())
+	}
+	record.Set("cursor", inc.NextCursor(startedAt))
+	record.Set("synced_at", startedAt)
+	if !incremental {
+		record.Set("full_sync_at", startedAt)
+	}
+	if err := app.Save(record); err != nil {
+		log.Printf("Failed to save sync cursor of %s: %v", provider.SourceName(), err)
+	}
+}
+
+// findSyncCursor returns the cursor record of a provider, or nil if there
+// is none or cursors are not stored.
+func findSyncCursor(app core.App, provider string) (*core.Record, error) {
+	collection, err := app.FindCollectionByNameOrId(SyncCursorsCollection)
+	if err != nil {
+		return nil, nil
+	}
+	records, err := app.FindRecordsByFilter(collection, "provider = {:provider}", "", 1, 0, map[string]any{"provider": provider})
+	if err != nil {
+		return nil, fmt.Errorf("finding sync cursor: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+	return records[0], nil
+}
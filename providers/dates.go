@@ -0,0 +1,240 @@
+package providers
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// monthNames maps English, Italian and German month names and their common
+// abbreviations to months.
+var monthNames = map[string]time.Month{
+	"january": time.January, "jan": time.January, "gennaio": time.January, "gen": time.January, "januar": time.January, "jänner": time.January, "jän": time.January,
+	"february": time.February, "feb": time.February, "febbraio": time.February, "februar": time.February,
+	"march": time.March, "mar": time.March, "marzo": time.March, "märz": time.March, "mär": time.March,
+	"april": time.April, "apr": time.April, "aprile": time.April,
+	"may": time.May, "maggio": time.May, "mag": time.May, "mai": time.May,
+	"june": time.June, "jun": time.June, "giugno": time.June, "giu": time.June, "juni": time.June,
+	"july": time.July, "jul": time.July, "luglio": time.July, "lug": time.July, "juli": time.July,
+	"august": time.August, "aug": time.August, "agosto": time.August, "ago": time.August,
+	"september": time.September, "sep": time.September, "sept": time.September, "settembre": time.September, "set": time.September,
+	"october": time.October, "oct": time.October, "ottobre": time.October, "ott": time.October, "oktober": time.October, "okt": time.October,
+	"november": time.November, "nov": time.November, "novembre": time.November,
+	"december": time.December, "dec": time.December, "dicembre": time.December, "dic": time.December, "dezember": time.December, "dez": time.December,
+}
+
+// ambiguousMonths are abbreviations that are also common English or Italian
+// words ("you may 2 ...", "set 3 tables", "2 set menus"). They only count as
+// a month when followed by a period or a year, or, after a day, when no word
+// follows them ("festa il 2 ago"). "2 may" is always a date.
+var ambiguousMonths = map[string]bool{
+	"may": true, "mar": true, "set": true, "ago": true, "mag": true,
+}
+
+var (
+	monthPattern = buildMonthPattern()
+
+	isoDateRe     = regexp.MustCompile(`\b(\d{4})-(\d{1,2})-(\d{1,2})\b`)
+	numericDateRe = regexp.MustCompile(`\b(\d{1,2})[./](\d{1,2})[./](\d{4}|\d{2})\b`)
+	dayMonthRe    = regexp.MustCompile(`(?i)\b(\d{1,2})(?:st|nd|rd|th)?\.?\s+` + monthPattern + `\b\.?,?(?:\s+(\d{4}))?\b`)
+	monthDayRe    = regexp.MustCompile(`(?i)\b` + monthPattern + `\.?\s+(\d{1,2})(?:st|nd|rd|th)?\b,?(?:\s+(\d{4}))?`)
+	clockTimeRe   = regexp.MustCompile(`\b([01]?\d|2[0-3])[:.]([0-5]\d)\b`)
+)
+
+// buildMonthPattern returns a regexp group matching any known month name,
+// longest names first so that "march" wins over "mar".
+func buildMonthPattern() string {
+	names := make([]string, 0, len(monthNames))
+	for name := range monthNames {
+		names = append(names, regexp.QuoteMeta(name))
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if len(names[i]) != len(names[j]) {
+			return len(names[i]) > len(names[j])
+		}
+		return names[i] < names[j]
+	})
+	return "(" + strings.Join(names, "|") + ")"
+}
+
+// parseFlexibleTime parses machine-readable timestamps in the formats commonly
+// found in feeds and APIs (RFC 3339, ISO 8601 without zone, RFC 1123/822).
+func parseFlexibleTime(s string, loc *time.Location) (time.Time, bool) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return time.Time{}, false
+	}
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	zoned := []string{
+		time.RFC3339,
+		time.RFC1123Z,
+		time.RFC1123,
+		time.RFC822Z,
+		time.RFC822,
+		"Mon, 2 Jan 2006 15:04:05 -0700",
+		"Mon, 2 Jan 2006 15:04:05 MST",
+		"2006-01-02T15:04:05-0700",
+		"2006-01-02T15:04-07:00",
+	}
+	for _, layout := range zoned {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, true
+		}
+	}
+
+	local := []string{
+		"2006-01-02T15:04:05",
+		"2006-01-02 15:04:05",
+		"2006-01-02T15:04",
+		"2006-01-02 15:04",
+		"2006-01-02",
+	}
+	for _, layout := range local {
+		if t, err := time.ParseInLocation(layout, s, loc); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// extractDate finds the first recognizable calendar date in free text, such as
+// "10.02.2026", "2026-02-10", "10 Feb 2026", "10 febbraio" or "February 10".
+// A time of day directly following the date ("18:30", "18.30") is applied too.
+// When the text omits the year, it is inferred from ref so that the result is
+// not more than a few months in the past.
+func extractDate(text string, ref time.Time, loc *time.Location) (time.Time, bool) {
+	if text == "" {
+		return time.Time{}, false
+	}
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	type candidate struct {
+		pos, end        int
+		year, month, dd int
+	}
+	var best *candidate
+	consider := func(c candidate) {
+		if c.month < 1 || c.month > 12 || c.dd < 1 || c.dd > 31 {
+			return
+		}
+		if best == nil || c.pos < best.pos {
+			best = &c
+		}
+	}
+
+	if m := isoDateRe.FindStringSubmatchIndex(text); m != nil {
+		consider(candidate{
+			pos: m[0], end: m[1],
+			year:  atoiSub(text, m, 1),
+			month: atoiSub(text, m, 2),
+			dd:    atoiSub(text, m, 3),
+		})
+	}
+	if m := numericDateRe.FindStringSubmatchIndex(text); m != nil {
+		year := atoiSub(text, m, 3)
+		if year < 100 {
+			year += 2000
+		}
+		consider(candidate{
+			pos: m[0], end: m[1],
+			year:  year,
+			month: atoiSub(text, m, 2),
+			dd:    atoiSub(text, m, 1),
+		})
+	}
+	for _, m := range dayMonthRe.FindAllStringSubmatchIndex(text, -1) {
+		name := strings.ToLower(text[m[4]:m[5]])
+		if !monthInContext(name, text[m[5]:], m[6] >= 0, true) {
+			continue
+		}
+		consider(candidate{
+			pos: m[0], end: m[1],
+			year:  atoiSub(text, m, 3),
+			month: int(monthNames[name]),
+			dd:    atoiSub(text, m, 1),
+		})
+		break
+	}
+	for _, m := range monthDayRe.FindAllStringSubmatchIndex(text, -1) {
+		name := strings.ToLower(text[m[2]:m[3]])
+		if !monthInContext(name, text[m[3]:], m[6] >= 0, false) {
+			continue
+		}
+		consider(candidate{
+			pos: m[0], end: m[1],
+			year:  atoiSub(text, m, 3),
+			month: int(monthNames[name]),
+			dd:    atoiSub(text, m, 2),
+		})
+		break
+	}
+
+	if best == nil {
+		return time.Time{}, false
+	}
+
+	year := best.year
+	inferYear := year == 0
+	if inferYear {
+		year = ref.Year()
+	}
+
+	hour, minute := 0, 0
+	rest := text[best.end:]
+	if len(rest) > 40 {
+		rest = rest[:40]
+	}
+	if m := clockTimeRe.FindStringSubmatch(rest); m != nil {
+		hour, _ = strconv.Atoi(m[1])
+		minute, _ = strconv.Atoi(m[2])
+	}
+
+	t := time.Date(year, time.Month(best.month), best.dd, hour, minute, 0, 0, loc)
+	if t.Day() != best.dd {
+		return time.Time{}, false // e.g. 31 February
+	}
+	if inferYear && t.Before(ref.AddDate(0, -3, 0)) {
+		t = t.AddDate(1, 0, 0)
+	}
+	return t, true
+}
+
+// monthInContext reports whether a month name found in a date, followed by
+// rest, is meant as a month, see ambiguousMonths. hasYear tells whether the
+// date has a year, dayFirst whether the day precedes the name.
+func monthInContext(name, rest string, hasYear, dayFirst bool) bool {
+	if !ambiguousMonths[name] || hasYear || strings.HasPrefix(rest, ".") {
+		return true
+	}
+	if !dayFirst {
+		return false
+	}
+	if name == "may" {
+		return true
+	}
+	rest = strings.TrimLeft(rest, " \t")
+	next, _ := utf8.DecodeRuneInString(rest)
+	return rest == "" || !unicode.IsLetter(next)
+}
+
+// atoiSub converts the n-th submatch of a FindStringSubmatchIndex result.
+// It returns 0 if the group did not participate in the match.
+func atoiSub(s string, m []int, n int) int {
+	if m[2*n] < 0 {
+		return 0
+	}
+	v, err := strconv.Atoi(s[m[2*n]:m[2*n+1]])
+	if err != nil {
+		return 0
+	}
+	return v
+}
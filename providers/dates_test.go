@@ -0,0 +1,60 @@
+package providers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtractDate(t *testing.T) {
+	ref := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+	lateRef := time.Date(2026, 11, 20, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		text     string
+		ref      time.Time
+		expected time.Time
+	}{
+		{"ISO", "Workshop on 2026-03-05 at 14:00", ref, time.Date(2026, 3, 5, 14, 0, 0, 0, time.UTC)},
+		{"Numeric dotted", "Konzert am 10.02.2026, Beginn 20.30 Uhr", ref, time.Date(2026, 2, 10, 20, 30, 0, 0, time.UTC)},
+		{"Numeric slashed short year", "Serata 07/03/26", ref, time.Date(2026, 3, 7, 0, 0, 0, 0, time.UTC)},
+		{"English day month", "Opening 10 Feb 2026 16:00-17:00", ref, time.Date(2026, 2, 10, 16, 0, 0, 0, time.UTC)},
+		{"English month day", "Join us on March 3rd, 2026", ref, time.Date(2026, 3, 3, 0, 0, 0, 0, time.UTC)},
+		{"Italian without year", "sabato 14 febbraio ore 21:00", ref, time.Date(2026, 2, 14, 21, 0, 0, 0, time.UTC)},
+		{"German with dot", "Am 5. März findet die Lesung statt", ref, time.Date(2026, 3, 5, 0, 0, 0, 0, time.UTC)},
+		{"Earliest match wins", "Dal 3 marzo al 2026-04-01", ref, time.Date(2026, 3, 3, 0, 0, 0, 0, time.UTC)},
+		{"Year rolls over", "10 gennaio", lateRef, time.Date(2027, 1, 10, 0, 0, 0, 0, time.UTC)},
+		{"Invalid day", "31.02.2026", ref, time.Time{}},
+		{"No date", "Our new opening hours", ref, time.Time{}},
+		{"Ambiguous may", "Members may 2 guests bring along", ref, time.Time{}},
+		{"Ambiguous set", "Please set 3 tables for the dinner", ref, time.Time{}},
+		{"Ambiguous ago", "Posted 5 days ago 12 comments", ref, time.Time{}},
+		{"Ambiguous mar", "Il mar 4 volte più blu", ref, time.Time{}},
+		{"Ambiguous word before a date", "You may join on May 3, 2026", ref, time.Date(2026, 5, 3, 0, 0, 0, 0, time.UTC)},
+		{"Ambiguous with year", "Concert May 3 2026", ref, time.Date(2026, 5, 3, 0, 0, 0, 0, time.UTC)},
+		{"Ambiguous with period", "Vernissage Mag. 14 ore 18:00", ref, time.Date(2026, 5, 14, 18, 0, 0, 0, time.UTC)},
+		{"Ambiguous day first", "Festa il 2 ago", ref, time.Date(2026, 8, 2, 0, 0, 0, 0, time.UTC)},
+		{"Ambiguous day first with time", "Festa il 2 ago, ore 21:00", ref, time.Date(2026, 8, 2, 21, 0, 0, 0, time.UTC)},
+		{"Ambiguous day first before a word", "Choose from 2 set menus", ref, time.Time{}},
+		{"Ambiguous day first before a later date", "2 set menus on 5 March", ref, time.Date(2026, 3, 5, 0, 0, 0, 0, time.UTC)},
+		{"Ambiguous day first with period", "Sagra 3 mar. ore 12:00", ref, time.Date(2026, 3, 3, 12, 0, 0, 0, time.UTC)},
+		{"Full may day first", "On 2 May we meet", ref, time.Date(2026, 5, 2, 0, 0, 0, 0, time.UTC)},
+		{"Month prefix of a word", "Open to 12 marketing students", ref, time.Time{}},
+		{"Month prefix of a word day first", "Sold 3 junior tickets", ref, time.Time{}},
+		{"Empty", "", ref, time.Time{}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, ok := extractDate(tc.text, tc.ref, time.UTC)
+			if tc.expected.IsZero() {
+				assert.False(t, ok)
+				return
+			}
+			assert.True(t, ok)
+			assert.Equal(t, tc.expected, got)
+		})
+	}
+}
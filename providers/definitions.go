@@ -0,0 +1,82 @@
+package providers
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Definition types understood by LoadSourceDefinitions.
+const (
+	// DefinitionTypeHTML selects SelectorScraperProvider (the default).
+	DefinitionTypeHTML = "html"
+	// DefinitionTypeJSONAPI selects JSONAPIProvider.
+	DefinitionTypeJSONAPI = "json_api"
+	// DefinitionTypeTribeEvents selects TribeEventsProvider.
+	DefinitionTypeTribeEvents = "tribe_events"
+)
+
+// ParseSourceDefinition creates a provider from a JSON definition. The "type"
+// property selects the provider kind and defaults to DefinitionTypeHTML.
+func ParseSourceDefinition(data []byte) (EventProvider, error) {
+	var header struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(data, &header); err != nil {
+		return nil, fmt.Errorf("decoding definition: %w", err)
+	}
+
+	switch header.Type {
+	case "", DefinitionTypeHTML:
+		return ParseScraperDefinition(data)
+	case DefinitionTypeJSONAPI:
+		var def JSONAPIDefinition
+		if err := json.Unmarshal(data, &def); err != nil {
+			return nil, fmt.Errorf("decoding json api definition: %w", err)
+		}
+		return NewJSONAPIProvider(def)
+	case DefinitionTypeTribeEvents:
+		var def struct {
+			SourceName string `json:"source_name"`
+			URL        string `json:"url"`
+			Category   string `json:"category"`
+			Location   string `json:"location"`
+		}
+		if err := json.Unmarshal(data, &def); err != nil {
+			return nil, fmt.Errorf("decoding tribe events definition: %w", err)
+		}
+		if def.SourceName == "" || def.URL == "" {
+			return nil, fmt.Errorf("tribe events definition: source_name and url are required")
+		}
+		p := NewTribeEventsProvider(def.SourceName, def.URL)
+		p.Category = def.Category
+		p.Location = def.Location
+		return p, nil
+	default:
+		return nil, fmt.Errorf("unknown definition type %q", header.Type)
+	}
+}
+
+// LoadSourceDefinitions creates a provider for every *.json file in dir.
+// A missing directory is not an error and yields no providers.
+func LoadSourceDefinitions(dir string) ([]EventProvider, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("listing source definitions: %w", err)
+	}
+
+	sources := make([]EventProvider, 0, len(files))
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", file, err)
+		}
+		p, err := ParseSourceDefinition(data)
+		if err != nil {
+			return nil, fmt.Errorf("loading %s: %w", file, err)
+		}
+		sources = append(sources, p)
+	}
+	return sources, nil
+}
@@ -0,0 +1,59 @@
+package providers
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadSourceDefinitions(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a.json"), []byte(`{
+		"source_name": "a", "url": "https://a.example.com", "item_selector": ".item",
+		"fields": {"title": {"selector": "h2"}}
+	}`), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "b.json"), []byte(`{
+		"type": "json_api", "source_name": "b", "url": "https://b.example.com",
+		"fields": {"title": "name", "date_start": "start"}
+	}`), 0o600))
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "c.json"), []byte(`{
+		"type": "tribe_events", "source_name": "c", "url": "https://c.example.com"
+	}`), 0o600))
+
+	sources, err := LoadSourceDefinitions(dir)
+	require.NoError(t, err)
+	require.Len(t, sources, 3)
+	assert.IsType(t, &SelectorScraperProvider{}, sources[0])
+	assert.IsType(t, &JSONAPIProvider{}, sources[1])
+	assert.Equal(t, "b", sources[1].SourceName())
+	require.IsType(t, &TribeEventsProvider{}, sources[2])
+	assert.Equal(t, "https://c.example.com/wp-json/tribe/events/v1/events", sources[2].(*TribeEventsProvider).BaseURL)
+
+	sources, err = LoadSourceDefinitions(filepath.Join(dir, "missing"))
+	require.NoError(t, err)
+	assert.Empty(t, sources)
+}
+
+func TestLoadSourceDefinitions_Invalid(t *testing.T) {
+	tests := []struct {
+		name       string
+		definition string
+	}{
+		{"Bad regex", `{"source_name": "x", "url": "https://x", "item_selector": ".i", "fields": {"title": {"regex": "("}}}`},
+		{"Missing title", `{"source_name": "x", "url": "https://x", "item_selector": ".i", "fields": {}}`},
+		{"Missing start", `{"type": "json_api", "source_name": "x", "url": "https://x", "fields": {"title": "t"}}`},
+		{"Unknown type", `{"type": "ftp", "source_name": "x"}`},
+		{"Not JSON", `nope`},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ParseSourceDefinition([]byte(tc.definition))
+			assert.Error(t, err)
+		})
+	}
+}
@@ -2,26 +2,42 @@ package providers
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
-	"html"
 	"log"
 	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
 	"time"
+
+	"github.com/PuerkitoBio/goquery"
 )
 
 // DrinbzProvider fetches events from the Drinbz WordPress API.
 type DrinbzProvider struct {
 	BaseURL string
 	Client  *http.Client
+	// TimeZone is used for the post dates and the event times in the posts,
+	// which are local to Bolzano.
+	TimeZone *time.Location
 }
 
 // NewDrinbzProvider creates a new Drinbz provider.
 // NewDrinbzProvider creates a new instance of DrinbzProvider.
 func NewDrinbzProvider() *DrinbzProvider {
+	loc, err := time.LoadLocation("Europe/Rome")
+	if err != nil {
+		log.Printf("Drinbz: loading time zone: %v, using UTC\n", err)
+		loc = time.UTC
+	}
 	return &DrinbzProvider{
-		BaseURL: "https://drinbz.it/wp-json/wp/v2/posts",
-		Client:  &http.Client{Timeout: 30 * time.Second},
+		BaseURL:  "https://drinbz.it/wp-json/wp/v2/posts",
+		Client:   SharedClient,
+		TimeZone: loc,
 	}
 }
 
@@ -44,42 +60,60 @@ type wpPost struct {
 	Embedded map[string]any `json:"_embedded,omitempty"` // For images if needed
 }
 
-// FetchEvents retrieves raw event data from the Drinbz API.
+// drinbzMaxPages caps the posts walked back in time; older roundups only
+// describe past events.
+const drinbzMaxPages = 5
+
+// FetchEvents retrieves the recent posts of the Drinbz API, following the
+// WordPress Link headers.
 func (p *DrinbzProvider) FetchEvents(ctx context.Context) ([]RawEvent, error) {
-	// Fetch recent posts. Drinbz posts are often events.
-	// API: https://drinbz.it/wp-json/wp/v2/posts?per_page=20
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.BaseURL, nil)
-	if err != nil {
-		return nil, fmt.Errorf("creating request: %w", err)
+	// Drinbz posts are often events.
+	// API: https://drinbz.it/wp-json/wp/v2/posts?per_page=100
+	paginator := Paginator{
+		Name:          p.SourceName(),
+		Strategy:      PaginateLink,
+		SizeParam:     "per_page",
+		PageSize:      100,
+		MaxPages:      drinbzMaxPages,
+		CapIsComplete: true, // see drinbzMaxPages
 	}
-
-	q := req.URL.Query()
-	q.Set("per_page", "20")
-	req.URL.RawQuery = q.Encode()
-
-	resp, err := p.Client.Do(req)
+	pageURL := p.BaseURL
+	if since := syncCursor(ctx); since != "" {
+		u, err := url.Parse(pageURL)
+		if err != nil {
+			return nil, fmt.Errorf("parsing url: %w", err)
+		}
+		q := u.Query()
+		q.Set("modified_after", since)
+		u.RawQuery = q.Encode()
+		pageURL = u.String()
+	}
+	events, err := paginator.Fetch(ctx, p.Client, pageURL, decodeDrinbzPage)
 	if err != nil {
-		return nil, fmt.Errorf("fetching posts: %w", err)
+		return nil, err
 	}
-	defer func() { _ = resp.Body.Close() }()
+	log.Printf("Drinbz: fetched %d posts\n", len(events))
+	return events, nil
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status: %d", resp.StatusCode)
-	}
+// NextCursor returns startedAt for the WordPress modified_after parameter, so
+// that the next sync only fetches new and edited posts.
+func (p *DrinbzProvider) NextCursor(startedAt time.Time) string {
+	return startedAt.UTC().Format(time.RFC3339)
+}
 
+// decodeDrinbzPage reads the posts of a page.
+func decodeDrinbzPage(resp *http.Response, _ string) ([]RawEvent, string, error) {
 	var posts []wpPost
 	if err := json.NewDecoder(resp.Body).Decode(&posts); err != nil {
-		return nil, fmt.Errorf("decoding response: %w", err)
+		return nil, "", fmt.Errorf("decoding response: %w", err)
 	}
 
 	events := make([]RawEvent, 0, len(posts))
-	log.Printf("Drinbz: fetched %d posts\n", len(posts))
 	for _, post := range posts {
 		// Convert struct to map for RawEvent interface
-		// In a real scenario we might just use the struct directly if the interface allowed it,
-		// but RawEvent is map[string]any.
 		raw := make(map[string]any)
-		raw["id"] = post.ID // int
+		raw["id"] = strconv.Itoa(post.ID) // string, so that archived payloads round-trip
 		raw["date"] = post.Date
 		raw["link"] = post.Link
 		raw["title"] = post.Title.Rendered
@@ -89,12 +123,24 @@ func (p *DrinbzProvider) FetchEvents(ctx context.Context) ([]RawEvent, error) {
 		// For now, we assume all posts on Drinbz "Next Week's Events" are relevant or we filter later.
 		events = append(events, RawEvent(raw))
 	}
-
-	return events, nil
+	return events, "", nil
 }
 
-// MapEvent converts a RawEvent into the internal Event structure.
+// MapEvent returns the first event of a post. The sync uses MapEvents, which
+// returns every event of a roundup post.
 func (p *DrinbzProvider) MapEvent(raw RawEvent) *Event {
+	events := p.MapEvents(raw)
+	if len(events) == 0 {
+		return nil
+	}
+	return events[0]
+}
+
+// MapEvents splits a Drinbz post into its individual events. Roundup posts
+// list many events, each with a title, a date (either in a day heading or on
+// its own line), an optional time, venue and link. Posts without any
+// recognizable event are skipped.
+func (p *DrinbzProvider) MapEvents(raw RawEvent) []*Event {
 	sprintOrEmpty := func(v any) string {
 		if v == nil {
 			return ""
@@ -102,34 +148,196 @@ func (p *DrinbzProvider) MapEvent(raw RawEvent) *Event {
 		return fmt.Sprint(v)
 	}
 
-	id := sprintOrEmpty(raw["id"])
-	title := sprintOrEmpty(raw["title"])
-	link := sprintOrEmpty(raw["link"])
+	id := valueToString(raw["id"])
+	postLink := sprintOrEmpty(raw["link"])
 	content := sprintOrEmpty(raw["content"])
 	dateStr := sprintOrEmpty(raw["date"])
 
-	// Parse date from WP format "2023-10-27T10:00:00"
-	dateStart, err := time.Parse("2006-01-02T15:04:05", dateStr)
+	loc := p.TimeZone
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	// The publish date is only used to infer the year of dates like "14 febbraio".
+	published, err := time.ParseInLocation("2006-01-02T15:04:05", dateStr, loc)
 	if err != nil {
 		log.Printf("Drinbz: failed to parse date %q: %v\n", dateStr, err)
-		dateStart = time.Now()
+		published = time.Now().In(loc)
+	}
+
+	items := parseRoundup(content, published)
+	if len(items) == 0 {
+		log.Printf("Drinbz: no events found in post %s\n", id)
+		return nil
+	}
+
+	events := make([]*Event, 0, len(items))
+	seen := make(map[string]int)
+	for _, item := range items {
+		// Derive the ID from the title so it stays stable when the post is
+		// edited, items move around or an event is moved to another date.
+		hash := sha256.Sum256([]byte(strings.ToLower(normalizeText(item.title))))
+		sourceID := id + "-" + hex.EncodeToString(hash[:6])
+		seen[sourceID]++
+		if n := seen[sourceID]; n > 1 {
+			sourceID = fmt.Sprintf("%s-%d", sourceID, n)
+		}
+
+		link := item.link
+		if link == "" {
+			link = postLink
+		}
+		location := item.venue
+		if location == "" {
+			location = "Bolzano"
+		}
+
+		events = append(events, &Event{
+			// Let PocketBase generate ID
+			Title:       item.title,
+			Description: item.description,
+			DateStart:   item.start,
+			DateEnd:     item.end,
+			Location:    location,
+			URL:         link,
+			Category:    "Other",
+			SourceName:  p.SourceName(),
+			SourceID:    sourceID,
+			Topics:      []string{},
+		})
 	}
+	return events
+}
+
+// roundupItem is a single event parsed from a roundup post.
+type roundupItem struct {
+	title       string
+	description string
+	venue       string
+	link        string
+	start       time.Time
+	end         time.Time
+}
+
+var (
+	// venuePrefixRe matches explicit venue labels at the start of a line.
+	venuePrefixRe = regexp.MustCompile(`(?i)^(?:📍|dove:|luogo:|location:|where:|ort:|wo:)\s*`)
+	// timeRangeRe matches "20:30-23:00", "20.30 – 23.00" etc.
+	timeRangeRe = regexp.MustCompile(`\b([01]?\d|2[0-3])[:.]([0-5]\d)\s*[-–—]\s*([01]?\d|2[0-3])[:.]([0-5]\d)\b`)
+	// timeVenueRe matches "ore 20:30 – Venue" and captures the venue part.
+	timeVenueRe = regexp.MustCompile(`(?i)^(?:ore|h|um|at)?\s*(?:[01]?\d|2[0-3])[:.][0-5]\d(?:\s*[-–—]\s*(?:[01]?\d|2[0-3])[:.][0-5]\d)?\s*(?:uhr)?\s*[-–—|,@]\s*(.+)$`)
+)
 
-	// Clean HTML from title (simple replacement)
-	title = html.UnescapeString(title)
-
-	return &Event{
-		// Let PocketBase generate ID
-		Title:       title,
-		Description: content, // This contains HTML, might need stripping in future
-		DateStart:   dateStart,
-		DateEnd:     dateStart.Add(2 * time.Hour), // Default duration
-		Location:    "Bolzano",                    // Default
-		URL:         link,
-		Category:    "Other", // Make sure category is set
-		SourceName:  p.SourceName(),
-		SourceID:    id,
-		IsNew:       true,
-		Topics:      []string{},
+// parseRoundup extracts events from the HTML body of a roundup post.
+// Dates without a year are resolved relative to published.
+func parseRoundup(content string, published time.Time) []roundupItem {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(content))
+	if err != nil {
+		return nil
 	}
+	doc.Find("br").ReplaceWithHtml("\n")
+
+	const blocks = "h1, h2, h3, h4, h5, h6, p, li"
+
+	var items []roundupItem
+	var day time.Time       // date from the latest day heading
+	var pendingTitle string // title from a heading without a date
+
+	doc.Find(blocks).Each(func(_ int, s *goquery.Selection) {
+		// Only look at innermost blocks to avoid reading text twice.
+		if s.Find(blocks).Length() > 0 {
+			return
+		}
+
+		var lines []string
+		for _, line := range strings.Split(s.Text(), "\n") {
+			if line = strings.Join(strings.Fields(line), " "); line != "" {
+				lines = append(lines, line)
+			}
+		}
+		if len(lines) == 0 {
+			return
+		}
+		text := strings.Join(lines, "\n")
+		isHeading := strings.HasPrefix(goquery.NodeName(s), "h")
+		emphasis := strings.TrimSpace(s.Find("strong, b").First().Text())
+
+		// A short block with a date and nothing else is a day heading,
+		// e.g. "Lunedì 16 febbraio".
+		if date, ok := extractDate(text, published, published.Location()); ok &&
+			len(lines) == 1 && len(text) <= 40 && !clockTimeRe.MatchString(text) {
+			day = date
+			pendingTitle = ""
+			return
+		}
+
+		// A heading without a date names the event described below it.
+		if isHeading {
+			pendingTitle = text
+			return
+		}
+
+		title := emphasis
+		rest := lines
+		if title == "" && pendingTitle != "" {
+			title = pendingTitle
+		} else if title == "" {
+			title = lines[0]
+			rest = lines[1:]
+		} else if lines[0] == title {
+			rest = lines[1:]
+		}
+		pendingTitle = ""
+
+		item := roundupItem{title: title}
+		if href, ok := s.Find("a[href]").First().Attr("href"); ok {
+			item.link = href
+		}
+
+		var details []string
+		for _, line := range rest {
+			switch {
+			case venuePrefixRe.MatchString(line):
+				item.venue = venuePrefixRe.ReplaceAllString(line, "")
+			case timeVenueRe.MatchString(line):
+				item.venue = timeVenueRe.FindStringSubmatch(line)[1]
+			default:
+				details = append(details, line)
+			}
+		}
+		item.description = strings.Join(details, "\n")
+
+		// Resolve the date: an explicit date in the block wins over the day heading.
+		start, ok := extractDate(text, published, published.Location())
+		if !ok {
+			if day.IsZero() {
+				return // not an event
+			}
+			start = day
+			if m := clockTimeRe.FindStringSubmatch(text); m != nil {
+				hour, _ := strconv.Atoi(m[1])
+				minute, _ := strconv.Atoi(m[2])
+				start = time.Date(day.Year(), day.Month(), day.Day(), hour, minute, 0, 0, day.Location())
+			}
+		}
+		item.start = start
+
+		item.end = start
+		if m := timeRangeRe.FindStringSubmatch(text); m != nil {
+			hour, _ := strconv.Atoi(m[3])
+			minute, _ := strconv.Atoi(m[4])
+			end := time.Date(start.Year(), start.Month(), start.Day(), hour, minute, 0, 0, start.Location())
+			if end.Before(start) {
+				end = end.AddDate(0, 0, 1) // past midnight
+			}
+			item.end = end
+		} else if start.Hour() == 0 && start.Minute() == 0 {
+			// No time given: treat it as an all-day event.
+			item.end = start.AddDate(0, 0, 1)
+		}
+
+		items = append(items, item)
+	})
+
+	return items
 }
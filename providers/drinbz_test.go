@@ -5,7 +5,9 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -24,6 +26,9 @@ func TestDrinbzProvider_FetchEvents(t *testing.T) {
 	}))
 	defer server.Close()
 
+	rome, err := time.LoadLocation("Europe/Rome")
+	require.NoError(t, err)
+
 	// Initialize provider with mock server URL
 	p := NewDrinbzProvider()
 	p.BaseURL = server.URL + "/wp-json/wp/v2/posts"
@@ -31,11 +36,59 @@ func TestDrinbzProvider_FetchEvents(t *testing.T) {
 	// Test Fetch
 	events, err := p.FetchEvents(context.Background())
 	require.NoError(t, err)
-	assert.Len(t, events, 1)
+	require.Len(t, events, 2)
+
+	// Test Map: the roundup post is split into its events
+	mapped := p.MapEvents(events[0])
+	require.Len(t, mapped, 4)
+
+	party := mapped[0]
+	assert.Equal(t, "Valentine’s Party", party.Title)
+	assert.Equal(t, "Club Max", party.Location)
+	assert.Equal(t, time.Date(2026, 2, 14, 21, 0, 0, 0, rome), party.DateStart)
+	assert.Equal(t, time.Date(2026, 2, 15, 2, 0, 0, 0, rome), party.DateEnd)
+	assert.Equal(t, "Live music and DJ set.", party.Description)
+	assert.Equal(t, "https://drinbz.it/eventi-della-settimana", party.URL)
+	assert.Equal(t, "drinbz", party.SourceName)
+	assert.Regexp(t, `^12400-[0-9a-f]{12}$`, party.SourceID)
+
+	market := mapped[1]
+	assert.Equal(t, "Mercatino dell’antiquariato", market.Title)
+	assert.Equal(t, "Piazza Walther", market.Location)
+	assert.Equal(t, "https://example.com/mercatino", market.URL)
+	assert.Equal(t, time.Date(2026, 2, 14, 0, 0, 0, 0, rome), market.DateStart)
+	assert.Equal(t, market.DateStart.AddDate(0, 0, 1), market.DateEnd)
+
+	brunch := mapped[2]
+	assert.Equal(t, "Jazz brunch", brunch.Title)
+	assert.Equal(t, "Café Plaza", brunch.Location)
+	assert.Equal(t, time.Date(2026, 2, 15, 11, 30, 0, 0, rome), brunch.DateStart)
+	assert.Equal(t, brunch.DateStart, brunch.DateEnd)
+
+	// An explicit date in the block wins over the day heading
+	aperitivo := mapped[3]
+	assert.Equal(t, "Aperitivo al Museion", aperitivo.Title)
+	assert.Equal(t, "Museion", aperitivo.Location)
+	assert.Equal(t, time.Date(2026, 2, 19, 18, 30, 0, 0, rome), aperitivo.DateStart)
+
+	// IDs are stable across runs
+	again := p.MapEvents(events[0])
+	for i := range mapped {
+		assert.Equal(t, mapped[i].SourceID, again[i].SourceID)
+	}
+
+	// and when an event is moved to another date
+	moved := RawEvent{}
+	for k, v := range events[0] {
+		moved[k] = v
+	}
+	moved["content"] = strings.Replace(events[0]["content"].(string), "19 febbraio", "20 febbraio", 1)
+	movedEvents := p.MapEvents(moved)
+	require.Len(t, movedEvents, 4)
+	assert.Equal(t, time.Date(2026, 2, 20, 18, 30, 0, 0, rome), movedEvents[3].DateStart)
+	assert.Equal(t, aperitivo.SourceID, movedEvents[3].SourceID)
 
-	// Test Map
-	mapped := p.MapEvent(events[0])
-	assert.Equal(t, "12345", mapped.SourceID)
-	assert.Equal(t, "Valentine's Party – Live Music", mapped.Title)
-	assert.Equal(t, "drinbz", mapped.SourceName)
+	// Posts without recognizable events are skipped
+	assert.Empty(t, p.MapEvents(events[1]))
+	assert.Nil(t, p.MapEvent(events[1]))
 }
@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
 	"time"
 )
 
@@ -21,7 +22,7 @@ type EuroHackathonsProvider struct {
 func NewEuroHackathonsProvider() *EuroHackathonsProvider {
 	return &EuroHackathonsProvider{
 		BaseURL: "https://euro-hackathons.vercel.app/api/hackathons",
-		Client:  &http.Client{Timeout: 30 * time.Second},
+		Client:  SharedClient,
 	}
 }
 
@@ -35,37 +36,33 @@ type hackathonsResponse struct {
 	Data []map[string]any `json:"data"`
 }
 
-// FetchEvents retrieves hackathon events from the Euro Hackathons API.
+// FetchEvents retrieves upcoming hackathons from the Euro Hackathons API,
+// following Link headers if the API paginates.
 func (p *EuroHackathonsProvider) FetchEvents(ctx context.Context) ([]RawEvent, error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.BaseURL, nil)
+	u, err := url.Parse(p.BaseURL)
 	if err != nil {
-		return nil, fmt.Errorf("creating request: %w", err)
+		return nil, fmt.Errorf("parsing url: %w", err)
 	}
-
-	q := req.URL.Query()
+	q := u.Query()
 	q.Set("status", "upcoming")
-	req.URL.RawQuery = q.Encode()
-
-	resp, err := p.Client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("fetching hackathons: %w", err)
-	}
-	defer func() { _ = resp.Body.Close() }()
+	u.RawQuery = q.Encode()
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status: %d", resp.StatusCode)
-	}
+	paginator := Paginator{Name: p.SourceName(), Strategy: PaginateLink}
+	return paginator.Fetch(ctx, p.Client, u.String(), decodeHackathonsPage)
+}
 
+// decodeHackathonsPage reads the hackathons of a page.
+func decodeHackathonsPage(resp *http.Response, _ string) ([]RawEvent, string, error) {
 	var result hackathonsResponse
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, fmt.Errorf("decoding response: %w", err)
+		return nil, "", fmt.Errorf("decoding response: %w", err)
 	}
 
 	events := make([]RawEvent, len(result.Data))
 	for i, item := range result.Data {
 		events[i] = RawEvent(item)
 	}
-	return events, nil
+	return events, "", nil
 }
 
 // MapEvent transforms raw hackathon data into a unified Event structure.
@@ -134,7 +131,6 @@ func (p *EuroHackathonsProvider) MapEvent(raw RawEvent) *Event {
 		SourceID:    id,
 		Topics:      topics,
 		Category:    "hackathon",
-		IsNew:       true,
 		Latitude:    0.0,
 		Longitude:   0.0,
 	}
@@ -0,0 +1,359 @@
+package providers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"html"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FeedSource describes a single RSS or Atom feed consumed by FeedProvider.
+type FeedSource struct {
+	// URL is the address of the RSS or Atom document.
+	URL string
+	// SourceName is stored on every event coming from this feed.
+	SourceName string
+	// Category is used for all events of this feed.
+	Category string
+	// Location is used when an item carries no location information.
+	Location string
+}
+
+// FeedProvider fetches events from RSS 2.0, RSS 1.0 and Atom feeds.
+// It understands the RSS event module (ev:startdate, ev:enddate, ev:location)
+// and GeoRSS points. Items without a machine-readable event date go through
+// extractDate and are skipped if no date can be found.
+type FeedProvider struct {
+	// Name is the identifier returned by SourceName.
+	Name string
+	// Feeds is the list of feeds to fetch.
+	Feeds []FeedSource
+	// TimeZone is used for dates that carry no zone information.
+	TimeZone *time.Location
+	// Client is the HTTP client used for requests.
+	Client *http.Client
+}
+
+// NewFeedProvider creates a new FeedProvider for the given feeds.
+func NewFeedProvider(feeds ...FeedSource) *FeedProvider {
+	return &FeedProvider{
+		Name:     "feed",
+		Feeds:    feeds,
+		TimeZone: time.UTC,
+		Client:   SharedClient,
+	}
+}
+
+// SourceName returns the unique identifier for this provider.
+func (p *FeedProvider) SourceName() string {
+	return p.Name
+}
+
+// feedDocument covers the root elements of RSS 2.0 (<rss><channel><item>),
+// RSS 1.0 (<rdf:RDF><item>) and Atom (<feed><entry>).
+type feedDocument struct {
+	Channel struct {
+		Items []feedItem `xml:"item"`
+		// AtomLinks may point to the next page of a paged feed (RFC 5005).
+		AtomLinks []feedLink `xml:"http://www.w3.org/2005/Atom link"`
+	} `xml:"channel"`
+	Items   []feedItem `xml:"item"`
+	Entries []feedItem `xml:"entry"`
+	Links   []feedLink `xml:"link"`
+}
+
+// next returns the rel="next" link of a paged feed, if any.
+func (d *feedDocument) next() string {
+	links := append(append([]feedLink(nil), d.Links...), d.Channel.AtomLinks...)
+	for _, link := range links {
+		if link.Rel == "next" && link.Href != "" {
+			return link.Href
+		}
+	}
+	return ""
+}
+
+// feedLink is an Atom <link> element; RSS links are carried as text.
+type feedLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr"`
+	Text string `xml:",chardata"`
+}
+
+// feedCategory is an RSS <category> (text) or Atom <category term="..."/>.
+type feedCategory struct {
+	Term string `xml:"term,attr"`
+	Text string `xml:",chardata"`
+}
+
+// feedItem is the union of the RSS item and Atom entry fields we care about.
+type feedItem struct {
+	Title       string         `xml:"title"`
+	Links       []feedLink     `xml:"link"`
+	GUID        string         `xml:"guid"`
+	ID          string         `xml:"id"`
+	Description string         `xml:"description"`
+	Summary     string         `xml:"summary"`
+	Content     string         `xml:"http://www.w3.org/2005/Atom content"`
+	Encoded     string         `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
+	PubDate     string         `xml:"pubDate"`
+	Published   string         `xml:"published"`
+	Updated     string         `xml:"updated"`
+	Categories  []feedCategory `xml:"category"`
+
+	StartDate string `xml:"http://purl.org/rss/1.0/modules/event/ startdate"`
+	EndDate   string `xml:"http://purl.org/rss/1.0/modules/event/ enddate"`
+	Location  string `xml:"http://purl.org/rss/1.0/modules/event/ location"`
+
+	GeoPoint string `xml:"http://www.georss.org/georss point"`
+	GeoLat   string `xml:"http://www.w3.org/2003/01/geo/wgs84_pos# lat"`
+	GeoLong  string `xml:"http://www.w3.org/2003/01/geo/wgs84_pos# long"`
+
+	Enclosure struct {
+		URL  string `xml:"url,attr"`
+		Type string `xml:"type,attr"`
+	} `xml:"enclosure"`
+	MediaContent struct {
+		URL string `xml:"url,attr"`
+	} `xml:"http://search.yahoo.com/mrss/ content"`
+}
+
+// FetchEvents downloads every configured feed and returns one raw event per item.
+// A failing feed is logged and skipped unless all feeds fail.
+func (p *FeedProvider) FetchEvents(ctx context.Context) ([]RawEvent, error) {
+	var events []RawEvent
+	var lastErr error
+	failed := 0
+
+	for _, feed := range p.Feeds {
+		feedEvents, err := p.fetchFeed(ctx, feed)
+		if err != nil {
+			log.Printf("Feed: failed to fetch %s: %v", feed.URL, err)
+			markPartial(ctx)
+			lastErr = err
+			failed++
+			continue
+		}
+		events = append(events, feedEvents...)
+	}
+
+	if failed > 0 && failed == len(p.Feeds) {
+		return nil, fmt.Errorf("all feeds failed: %w", lastErr)
+	}
+	return events, nil
+}
+
+// fetchFeed downloads and decodes a single feed, following the next links
+// of paged feeds.
+func (p *FeedProvider) fetchFeed(ctx context.Context, feed FeedSource) ([]RawEvent, error) {
+	paginator := Paginator{Name: p.SourceName(), Strategy: PaginateCursor}
+	return paginator.Fetch(ctx, p.Client, feed.URL, func(resp *http.Response, _ string) ([]RawEvent, string, error) {
+		var doc feedDocument
+		decoder := xml.NewDecoder(resp.Body)
+		decoder.Strict = false
+		decoder.Entity = xml.HTMLEntity
+		if err := decoder.Decode(&doc); err != nil {
+			return nil, "", fmt.Errorf("decoding feed: %w", err)
+		}
+
+		var items []feedItem
+		items = append(items, doc.Channel.Items...)
+		items = append(items, doc.Items...)
+		items = append(items, doc.Entries...)
+
+		events := make([]RawEvent, 0, len(items))
+		for _, item := range items {
+			events = append(events, item.toRaw(feed))
+		}
+		return events, doc.next(), nil
+	})
+}
+
+// toRaw flattens a feed item into a RawEvent.
+func (item feedItem) toRaw(feed FeedSource) RawEvent {
+	link := ""
+	for _, l := range item.Links {
+		href := strings.TrimSpace(l.Href)
+		if href == "" {
+			href = strings.TrimSpace(l.Text)
+		}
+		if href != "" && (l.Rel == "" || l.Rel == "alternate") {
+			link = href
+			break
+		}
+	}
+
+	id := strings.TrimSpace(item.GUID)
+	if id == "" {
+		id = strings.TrimSpace(item.ID)
+	}
+
+	description := item.Encoded
+	for _, d := range []string{item.Content, item.Description, item.Summary} {
+		if description == "" {
+			description = d
+		}
+	}
+
+	published := item.PubDate
+	for _, d := range []string{item.Published, item.Updated} {
+		if published == "" {
+			published = d
+		}
+	}
+
+	var categories []any
+	for _, c := range item.Categories {
+		name := strings.TrimSpace(c.Term)
+		if name == "" {
+			name = strings.TrimSpace(c.Text)
+		}
+		if name != "" {
+			categories = append(categories, name)
+		}
+	}
+
+	image := item.MediaContent.URL
+	if image == "" && strings.HasPrefix(item.Enclosure.Type, "image/") {
+		image = item.Enclosure.URL
+	}
+
+	raw := RawEvent{
+		"id":          id,
+		"title":       strings.TrimSpace(item.Title),
+		"link":        link,
+		"description": description,
+		"published":   strings.TrimSpace(published),
+		"start":       strings.TrimSpace(item.StartDate),
+		"end":         strings.TrimSpace(item.EndDate),
+		"location":    strings.TrimSpace(item.Location),
+		"image":       image,
+		"categories":  categories,
+		"source_name": feed.SourceName,
+		"category":    feed.Category,
+		"feed_url":    feed.URL,
+		"default_loc": feed.Location,
+	}
+
+	lat, long := parseGeoPoint(item.GeoPoint)
+	if lat == 0 && long == 0 {
+		lat, _ = strconv.ParseFloat(strings.TrimSpace(item.GeoLat), 64)
+		long, _ = strconv.ParseFloat(strings.TrimSpace(item.GeoLong), 64)
+	}
+	if lat != 0 || long != 0 {
+		raw["latitude"] = lat
+		raw["longitude"] = long
+	}
+
+	return raw
+}
+
+// parseGeoPoint parses a GeoRSS "lat long" point.
+func parseGeoPoint(s string) (float64, float64) {
+	fields := strings.Fields(s)
+	if len(fields) != 2 {
+		return 0, 0
+	}
+	lat, errLat := strconv.ParseFloat(fields[0], 64)
+	long, errLong := strconv.ParseFloat(fields[1], 64)
+	if errLat != nil || errLong != nil {
+		return 0, 0
+	}
+	return lat, long
+}
+
+// MapEvent converts a RawEvent into the internal Event structure.
+// Items whose event date can neither be read nor extracted are dropped.
+func (p *FeedProvider) MapEvent(raw RawEvent) *Event {
+	title, _ := raw["title"].(string)
+	title = html.UnescapeString(title)
+	if title == "" {
+		return nil
+	}
+	link, _ := raw["link"].(string)
+	rawDescription, _ := raw["description"].(string)
+	description := stripHTML(rawDescription)
+
+	dateStart, ok := parseFlexibleTime(fmt.Sprint(raw["start"]), p.TimeZone)
+	if !ok {
+		// No machine-readable event date: look for one in the text, using the
+		// publish date as reference for dates without a year.
+		ref := time.Now()
+		if published, ok := parseFlexibleTime(fmt.Sprint(raw["published"]), p.TimeZone); ok {
+			ref = published
+		}
+		dateStart, ok = extractDate(title+"\n"+description, ref, p.TimeZone)
+		if !ok {
+			log.Printf("Feed: skipping %q, no event date found", title)
+			return nil
+		}
+	}
+
+	dateEnd, ok := parseFlexibleTime(fmt.Sprint(raw["end"]), p.TimeZone)
+	if !ok || dateEnd.Before(dateStart) {
+		dateEnd = dateStart.Add(2 * time.Hour)
+	}
+
+	id, _ := raw["id"].(string)
+	if id == "" {
+		id = link
+	}
+	if id == "" {
+		hash := sha256.Sum256([]byte(title + dateStart.String()))
+		id = hex.EncodeToString(hash[:8])
+	}
+	if link == "" {
+		// Events need a URL, the feed is the best we have
+		link, _ = raw["feed_url"].(string)
+	}
+
+	sourceName, _ := raw["source_name"].(string)
+	if sourceName == "" {
+		sourceName = p.SourceName()
+	}
+	category, _ := raw["category"].(string)
+	if category == "" {
+		category = "general"
+	}
+	location, _ := raw["location"].(string)
+	if location == "" {
+		location, _ = raw["default_loc"].(string)
+	}
+
+	topics := []string{}
+	if cats, ok := raw["categories"].([]any); ok {
+		for _, c := range cats {
+			if s, ok := c.(string); ok {
+				topics = append(topics, s)
+			}
+		}
+	}
+
+	image, _ := raw["image"].(string)
+	lat, _ := raw["latitude"].(float64)
+	long, _ := raw["longitude"].(float64)
+
+	return &Event{
+		ID:          id,
+		Title:       title,
+		Description: description,
+		DateStart:   dateStart,
+		DateEnd:     dateEnd,
+		Location:    location,
+		URL:         link,
+		ImageURL:    image,
+		SourceName:  sourceName,
+		SourceID:    id,
+		Topics:      topics,
+		Category:    category,
+		Latitude:    lat,
+		Longitude:   long,
+	}
+}
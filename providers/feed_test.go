@@ -0,0 +1,108 @@
+package providers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFeedProvider_FetchEvents_RSS(t *testing.T) {
+	fixture, err := os.ReadFile("fixtures/feed.xml")
+	require.NoError(t, err)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/events.rss", r.URL.Path)
+		w.Header().Set("Content-Type", "application/rss+xml")
+		_, _ = w.Write(fixture)
+	}))
+	defer server.Close()
+
+	p := NewFeedProvider(FeedSource{
+		URL:        server.URL + "/events.rss",
+		SourceName: "kulturzentrum",
+		Category:   "Art & Culture",
+		Location:   "Bolzano",
+	})
+
+	events, err := p.FetchEvents(context.Background())
+	require.NoError(t, err)
+	require.Len(t, events, 3)
+
+	jazz := p.MapEvent(events[0])
+	require.NotNil(t, jazz)
+	assert.Equal(t, "evt-1001", jazz.SourceID)
+	assert.Equal(t, "Jazz Night & Friends", jazz.Title)
+	assert.Equal(t, "An evening of live jazz.", jazz.Description)
+	assert.Equal(t, "Kulturzentrum, Bozen", jazz.Location)
+	assert.Equal(t, "kulturzentrum", jazz.SourceName)
+	assert.Equal(t, "Art & Culture", jazz.Category)
+	assert.Equal(t, []string{"Music", "Jazz"}, jazz.Topics)
+	assert.Equal(t, "https://kultur.example.com/img/jazz.jpg", jazz.ImageURL)
+	assert.InDelta(t, 46.4983, jazz.Latitude, 0.0001)
+	assert.Equal(t, time.Date(2030, 12, 20, 19, 0, 0, 0, time.UTC), jazz.DateStart.UTC())
+	assert.Equal(t, 3*time.Hour, jazz.DateEnd.Sub(jazz.DateStart))
+
+	// No ev:startdate: the date is extracted from the text, and the year is
+	// inferred from the publish date (December 2030 -> January 2031).
+	poetry := p.MapEvent(events[1])
+	require.NotNil(t, poetry)
+	assert.Equal(t, "https://kultur.example.com/events/poesie", poetry.SourceID)
+	assert.Equal(t, "Bolzano", poetry.Location)
+	assert.Equal(t, time.Date(2031, 1, 15, 18, 30, 0, 0, time.UTC), poetry.DateStart)
+
+	// No date anywhere: skipped instead of pretending it happens now.
+	assert.Nil(t, p.MapEvent(events[2]))
+}
+
+func TestFeedProvider_FetchEvents_Atom(t *testing.T) {
+	atom := `<?xml version="1.0" encoding="utf-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+  <title>Meetups</title>
+  <entry>
+    <title>Go Meetup 12.03.2030 19:00</title>
+    <link rel="alternate" href="https://meetups.example.com/go-march"/>
+    <id>urn:uuid:1225c695-cfb8-4ebb-aaaa-80da344efa6a</id>
+    <updated>2030-02-01T10:00:00Z</updated>
+    <category term="Go"/>
+    <summary>Monthly Go meetup.</summary>
+  </entry>
+  <entry>
+    <title>Rust Meetup 19.03.2030 19:00</title>
+    <id>urn:uuid:7d1f2c3e-0b4a-4c5d-bbbb-91eb455fab7b</id>
+    <updated>2030-02-01T10:00:00Z</updated>
+  </entry>
+</feed>`
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/atom+xml")
+		_, _ = w.Write([]byte(atom))
+	}))
+	defer server.Close()
+
+	p := NewFeedProvider(FeedSource{URL: server.URL, SourceName: "meetups"})
+
+	events, err := p.FetchEvents(context.Background())
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+
+	ev := p.MapEvent(events[0])
+	require.NotNil(t, ev)
+	assert.Equal(t, "urn:uuid:1225c695-cfb8-4ebb-aaaa-80da344efa6a", ev.SourceID)
+	assert.Equal(t, "https://meetups.example.com/go-march", ev.URL)
+	assert.Equal(t, "Monthly Go meetup.", ev.Description)
+	assert.Equal(t, []string{"Go"}, ev.Topics)
+	assert.Equal(t, "general", ev.Category)
+	assert.Equal(t, time.Date(2030, 3, 12, 19, 0, 0, 0, time.UTC), ev.DateStart)
+
+	// Entries without a link point to the feed
+	ev = p.MapEvent(events[1])
+	require.NotNil(t, ev)
+	assert.Equal(t, "urn:uuid:7d1f2c3e-0b4a-4c5d-bbbb-91eb455fab7b", ev.SourceID)
+	assert.Equal(t, server.URL, ev.URL)
+}
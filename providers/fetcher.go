@@ -0,0 +1,159 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// SharedClient is the HTTP client of all providers, so that sources on the
+// same host share connections.
+var SharedClient = &http.Client{Timeout: 30 * time.Second}
+
+// Fetch settings shared by all providers.
+var (
+	// UserAgent identifies Venvi to the sources. It is also the agent matched
+	// against robots.txt groups, up to the first "/".
+	UserAgent = "Venvi/1.0 (event aggregator)"
+	// MaxRetries is the number of retries of a request answered with 429 or 5xx.
+	MaxRetries = 3
+	// RetryBaseDelay is the backoff before the first retry; it doubles with
+	// every further retry, with jitter.
+	RetryBaseDelay = time.Second
+	// RetryMaxDelay caps the backoff. A Retry-After asking for longer is not
+	// waited for and the response is returned as is.
+	RetryMaxDelay = time.Minute
+	// HostInterval is the minimum time between two requests to the same host,
+	// unless its robots.txt asks for a longer Crawl-delay.
+	HostInterval = time.Second
+)
+
+// hosts spaces out the requests of all providers per host.
+var hosts = struct {
+	sync.Mutex
+	next     map[string]time.Time
+	interval map[string]time.Duration
+}{next: make(map[string]time.Time), interval: make(map[string]time.Duration)}
+
+// sendRequest sends a request with the shared User-Agent, waiting for its
+// turn on the host and retrying 429 and 5xx responses with exponential
+// backoff, honoring Retry-After.
+func sendRequest(client *http.Client, req *http.Request) (*http.Response, error) {
+	if req.Header.Get("User-Agent") == "" {
+		req = req.Clone(req.Context())
+		req.Header.Set("User-Agent", UserAgent)
+	}
+	retryable := req.Method == http.MethodGet || req.Method == http.MethodHead
+
+	for attempt := 0; ; attempt++ {
+		if err := waitForHost(req.Context(), req.URL.Host); err != nil {
+			return nil, err
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		if !retryable || attempt >= MaxRetries || !shouldRetry(resp.StatusCode) {
+			return resp, nil
+		}
+
+		delay := backoff(attempt)
+		if after, ok := retryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+			if after > RetryMaxDelay {
+				return resp, nil
+			}
+			delay = after
+		}
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+
+		if err := sleep(req.Context(), delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// shouldRetry reports whether a status is worth retrying.
+func shouldRetry(status int) bool {
+	return status == http.StatusTooManyRequests || status >= 500
+}
+
+// backoff returns the delay before retry attempt+1: RetryBaseDelay doubled
+// per attempt, capped at RetryMaxDelay, with jitter in its upper half.
+func backoff(attempt int) time.Duration {
+	delay := RetryBaseDelay << attempt
+	if delay > RetryMaxDelay || delay <= 0 {
+		delay = RetryMaxDelay
+	}
+	half := delay / 2
+	if half <= 0 {
+		return delay
+	}
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+// retryAfter parses a Retry-After header, given in seconds or as a date.
+func retryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if d := date.Sub(now); d > 0 {
+			return d, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+// waitForHost blocks until the host may be requested again and reserves
+// the next slot.
+func waitForHost(ctx context.Context, host string) error {
+	hosts.Lock()
+	interval := HostInterval
+	if custom, ok := hosts.interval[host]; ok && custom > interval {
+		interval = custom
+	}
+	now := time.Now()
+	start := hosts.next[host]
+	if start.Before(now) {
+		start = now
+	}
+	hosts.next[host] = start.Add(interval)
+	hosts.Unlock()
+
+	return sleep(ctx, time.Until(start))
+}
+
+// setHostInterval sets a host-specific minimum interval, e.g. a Crawl-delay.
+func setHostInterval(host string, interval time.Duration) {
+	hosts.Lock()
+	defer hosts.Unlock()
+
+	hosts.interval[host] = interval
+}
+
+// sleep waits for d or until ctx is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("waiting to fetch: %w", ctx.Err())
+	}
+}
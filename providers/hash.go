@@ -0,0 +1,41 @@
+package providers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"slices"
+	"strings"
+	"time"
+)
+
+// contentHash returns a hash of the stored content of an event. Whitespace,
+// time zones and the order of topics are normalized away, so that a source
+// re-serializing the same event does not count as a change.
+func contentHash(event *Event) string {
+	topics := make([]string, 0, len(event.Topics))
+	for _, topic := range event.Topics {
+		topics = append(topics, normalizeText(topic))
+	}
+	slices.Sort(topics)
+
+	normalized, _ := json.Marshal([]any{
+		normalizeText(event.Title),
+		normalizeText(event.Description),
+		event.DateStart.UTC().Truncate(time.Second).Format(time.RFC3339),
+		event.DateEnd.UTC().Truncate(time.Second).Format(time.RFC3339),
+		normalizeText(event.Location),
+		strings.TrimSpace(event.URL),
+		strings.TrimSpace(event.ImageURL),
+		topics,
+		normalizeText(event.Category),
+		event.Status,
+	})
+	sum := sha256.Sum256(normalized)
+	return hex.EncodeToString(sum[:])
+}
+
+// normalizeText collapses runs of whitespace and trims s.
+func normalizeText(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
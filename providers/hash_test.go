@@ -0,0 +1,34 @@
+package providers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContentHash(t *testing.T) {
+	start := time.Date(2030, 1, 1, 18, 0, 0, 0, time.UTC)
+	event := &Event{
+		Title:     "Jazz night",
+		DateStart: start,
+		DateEnd:   start.Add(2 * time.Hour),
+		Location:  "Bolzano",
+		Topics:    []string{"music", "jazz"},
+	}
+
+	// Formatting differences are not changes
+	same := *event
+	same.Title = "  Jazz   night "
+	same.DateStart = start.In(time.FixedZone("CET", 3600))
+	same.Topics = []string{"jazz", "music"}
+	assert.Equal(t, contentHash(event), contentHash(&same))
+
+	moved := *event
+	moved.DateStart = start.Add(24 * time.Hour)
+	assert.NotEqual(t, contentHash(event), contentHash(&moved))
+
+	renamed := *event
+	renamed.Title = "Jazz night (sold out)"
+	assert.NotEqual(t, contentHash(event), contentHash(&renamed))
+}
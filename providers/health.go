@@ -0,0 +1,186 @@
+package providers
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/pocketbase/pocketbase/core"
+)
+
+// SyncRunsCollection is the PocketBase collection recording every sync of
+// every provider.
+const SyncRunsCollection = "sync_runs"
+
+// StaleAfter is how long a provider may go without a successful sync before
+// its health reports it as stale.
+var StaleAfter = 24 * time.Hour
+
+// healthWindow is the number of recent runs inspected per provider.
+const healthWindow = 50
+
+// Provider health statuses.
+const (
+	HealthOK       = "ok"
+	HealthDegraded = "degraded"
+	HealthFailing  = "failing"
+	HealthStale    = "stale"
+	HealthUnknown  = "unknown"
+)
+
+// ProviderHealth summarizes the recent sync runs of a provider.
+type ProviderHealth struct {
+	Provider string `json:"provider"`
+	// Status is one of HealthOK, HealthDegraded, HealthFailing, HealthStale
+	// or HealthUnknown.
+	Status    string     `json:"status"`
+	LastRunAt *time.Time `json:"last_run_at"`
+	// LastRunState is the provider state of the last run, e.g. "done" or "timeout".
+	LastRunState  string     `json:"last_run_state,omitempty"`
+	LastSuccessAt *time.Time `json:"last_success_at"`
+	// ConsecutiveFailures counts failed, timed out or degraded runs since the
+	// last success.
+	ConsecutiveFailures int      `json:"consecutive_failures"`
+	Stale               bool     `json:"stale"`
+	LastErrors          []string `json:"last_errors,omitempty"`
+	LastWarnings        []string `json:"last_warnings,omitempty"`
+	// LastStats are the stats of the last run.
+	LastStats *SyncStats `json:"last_stats,omitempty"`
+}
+
+// recordSyncRun stores the outcome of a provider sync. Failures are logged,
+// as they must not fail the sync itself. Runs are not recorded if the
+// collection does not exist.
+func recordSyncRun(app core.App, jobID, state string, stats SyncStats, startedAt, finishedAt time.Time) {
+	collection, err := app.FindCollectionByNameOrId(SyncRunsCollection)
+	if err != nil {
+		return
+	}
+
+	record := core.NewRecord(collection)
+	record.Set("provider", stats.Provider)
+	record.Set("job_id", jobID)
+	record.Set("state", state)
+	record.Set("started_at", startedAt)
+	record.Set("finished_at", finishedAt)
+	record.Set("duration_ms", finishedAt.Sub(startedAt).Milliseconds())
+	record.Set("fetched", stats.Fetched)
+	record.Set("mapped", stats.Mapped)
+	record.Set("skipped", stats.Skipped)
+	record.Set("new", stats.New)
+	record.Set("updated", stats.Updated)
+	record.Set("unchanged", stats.Unchanged)
+	record.Set("errors", stats.Errors)
+	record.Set("timeouts", stats.Timeouts)
+	record.Set("error_messages", stats.ErrorMessages)
+	record.Set("warnings", stats.Warnings)
+	record.Set("not_modified", stats.NotModified)
+	record.Set("incremental", stats.Incremental)
+	record.Set("removed", stats.Removed)
+	record.Set("partial", stats.Partial)
+
+	if err := app.Save(record); err != nil {
+		log.Printf("Failed to record sync run of %s: %v", stats.Provider, err)
+	}
+}
+
+// ProvidersHealth computes the health of every active provider from its
+// recent sync runs.
+func ProvidersHealth(app core.App) ([]ProviderHealth, error) {
+	active, err := ActiveProviders(app)
+	if err != nil {
+		return nil, err
+	}
+	collection, err := app.FindCollectionByNameOrId(SyncRunsCollection)
+	if err != nil {
+		return nil, fmt.Errorf("finding sync runs collection: %w", err)
+	}
+
+	result := make([]ProviderHealth, 0, len(active))
+	for _, p := range active {
+		runs, err := app.FindRecordsByFilter(
+			collection,
+			"provider = {:provider}",
+			"-started_at",
+			healthWindow,
+			0,
+			map[string]any{"provider": p.SourceName()},
+		)
+		if err != nil {
+			return nil, fmt.Errorf("loading sync runs of %s: %w", p.SourceName(), err)
+		}
+		result = append(result, providerHealth(p.SourceName(), runs, time.Now()))
+	}
+	return result, nil
+}
+
+// providerHealth summarizes runs, which are sorted newest first.
+func providerHealth(provider string, runs []*core.Record, now time.Time) ProviderHealth {
+	health := ProviderHealth{Provider: provider, Status: HealthUnknown}
+
+	for _, run := range runs {
+		state := run.GetString("state")
+		if state == ProviderCancelled {
+			continue // says nothing about the source
+		}
+
+		if health.LastRunAt == nil {
+			lastRunAt := run.GetDateTime("started_at").Time()
+			health.LastRunAt = &lastRunAt
+			health.LastRunState = state
+			health.LastStats = syncRunStats(run)
+			health.LastErrors = health.LastStats.ErrorMessages
+			health.LastWarnings = health.LastStats.Warnings
+		}
+
+		if state == ProviderDone {
+			lastSuccessAt := run.GetDateTime("finished_at").Time()
+			health.LastSuccessAt = &lastSuccessAt
+			break
+		}
+		health.ConsecutiveFailures++
+	}
+
+	if health.LastRunAt == nil {
+		return health
+	}
+
+	health.Stale = health.LastSuccessAt == nil || now.Sub(*health.LastSuccessAt) > StaleAfter
+	switch {
+	case health.LastRunState == ProviderDegraded:
+		health.Status = HealthDegraded
+	case health.ConsecutiveFailures > 0:
+		health.Status = HealthFailing
+	case health.Stale:
+		health.Status = HealthStale
+	default:
+		health.Status = HealthOK
+	}
+	return health
+}
+
+// syncRunStats reads the stats stored in a sync run record.
+func syncRunStats(run *core.Record) *SyncStats {
+	stats := &SyncStats{
+		Provider:    run.GetString("provider"),
+		New:         run.GetInt("new"),
+		Updated:     run.GetInt("updated"),
+		Unchanged:   run.GetInt("unchanged"),
+		Errors:      run.GetInt("errors"),
+		Timeouts:    run.GetInt("timeouts"),
+		Fetched:     run.GetInt("fetched"),
+		Mapped:      run.GetInt("mapped"),
+		Skipped:     run.GetInt("skipped"),
+		NotModified: run.GetBool("not_modified"),
+		Incremental: run.GetBool("incremental"),
+		Removed:     run.GetInt("removed"),
+		Partial:     run.GetBool("partial"),
+	}
+	if err := run.UnmarshalJSONField("error_messages", &stats.ErrorMessages); err != nil {
+		log.Printf("Sync run %s: invalid error messages: %v", run.Id, err)
+	}
+	if err := run.UnmarshalJSONField("warnings", &stats.Warnings); err != nil {
+		log.Printf("Sync run %s: invalid warnings: %v", run.Id, err)
+	}
+	return stats
+}
@@ -4,7 +4,10 @@ import (
 	"crypto/sha256"
 	"fmt"
 	"log"
+	"strings"
 	"time"
+
+	"github.com/PuerkitoBio/goquery"
 )
 
 // extractLocalized extracts a localized value from ODH detail objects.
@@ -168,8 +171,23 @@ func buildEventFromRaw(raw RawEvent, sourceName, defaultLocation, defaultURL str
 		SourceID:    rawID,
 		Topics:      []string{},
 		Category:    "general",
-		IsNew:       true,
 		Latitude:    lat,
 		Longitude:   long,
 	}
 }
+
+// stripHTML returns the visible text of an HTML fragment with whitespace collapsed.
+func stripHTML(s string) string {
+	if !strings.ContainsAny(s, "<&") {
+		return strings.Join(strings.Fields(s), " ")
+	}
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(s))
+	if err != nil {
+		return strings.Join(strings.Fields(s), " ")
+	}
+	// Keep block boundaries as spaces so that adjacent paragraphs don't merge.
+	doc.Find("br, p, div, li, h1, h2, h3, h4, h5, h6").Each(func(_ int, sel *goquery.Selection) {
+		sel.AppendHtml(" ")
+	})
+	return strings.Join(strings.Fields(doc.Text()), " ")
+}
@@ -0,0 +1,273 @@
+package providers
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/filesystem"
+)
+
+// HTTPCacheCollection is the PocketBase collection storing the validators
+// (ETag, Last-Modified) and bodies of provider responses, keyed by URL.
+const HTTPCacheCollection = "http_cache"
+
+// httpCacheKey is the context key of the cache of a provider sync.
+type httpCacheKey struct{}
+
+// cachedResponse is a stored response of a URL.
+type cachedResponse struct {
+	ETag         string
+	LastModified string
+	Body         []byte
+}
+
+// httpCache makes the requests of one provider sync conditional. Responses
+// only become the new cache entries once committed, after all their events
+// have been written, so that a failed sync is retried in full.
+type httpCache struct {
+	app core.App
+
+	mu          sync.Mutex
+	pending     map[string]cachedResponse
+	requests    int
+	notModified int
+	// batchStart holds the counts at the end of the previous streamed batch.
+	batchStart [2]int
+}
+
+// newHTTPCache returns a cache for one provider sync, or nil if the cache
+// collection does not exist.
+func newHTTPCache(app core.App) *httpCache {
+	if _, err := app.FindCollectionByNameOrId(HTTPCacheCollection); err != nil {
+		return nil
+	}
+	return &httpCache{app: app, pending: make(map[string]cachedResponse)}
+}
+
+// withHTTPCache makes the requests sent by doRequest with ctx conditional.
+func withHTTPCache(ctx context.Context, cache *httpCache) context.Context {
+	if cache == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, httpCacheKey{}, cache)
+}
+
+// doRequest sends a provider request. Every provider fetches through it, so
+// that GET requests of a sync are conditional: stored validators are sent as
+// If-None-Match/If-Modified-Since, and a 304 is answered from the stored
+// body, so providers need no special handling.
+func doRequest(client *http.Client, req *http.Request) (*http.Response, error) {
+	cache, _ := req.Context().Value(httpCacheKey{}).(*httpCache)
+	if cache == nil || req.Method != http.MethodGet {
+		return sendRequest(client, req)
+	}
+
+	// Callers may reuse req for several pages
+	req = req.Clone(req.Context())
+	key := req.URL.String()
+	cached, ok := cache.lookup(key)
+	if ok {
+		if cached.ETag != "" {
+			req.Header.Set("If-None-Match", cached.ETag)
+		}
+		if cached.LastModified != "" {
+			req.Header.Set("If-Modified-Since", cached.LastModified)
+		}
+	}
+
+	resp, err := sendRequest(client, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusNotModified && ok {
+		_ = resp.Body.Close()
+		cache.count(true)
+		resp.StatusCode = http.StatusOK
+		resp.Status = "200 OK"
+		resp.Body = io.NopCloser(bytes.NewReader(cached.Body))
+		resp.ContentLength = int64(len(cached.Body))
+		return resp, nil
+	}
+	cache.count(false)
+
+	etag, lastModified := resp.Header.Get("ETag"), resp.Header.Get("Last-Modified")
+	if resp.StatusCode != http.StatusOK || (etag == "" && lastModified == "") {
+		return resp, nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("reading response: %w", err)
+	}
+	cache.store(key, cachedResponse{ETag: etag, LastModified: lastModified, Body: body})
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	return resp, nil
+}
+
+// count records a response.
+func (c *httpCache) count(notModified bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.requests++
+	if notModified {
+		c.notModified++
+	}
+}
+
+// unchanged reports whether every request of the sync returned 304, in which
+// case the fetched events are the ones already stored.
+func (c *httpCache) unchanged() bool {
+	if c == nil {
+		return false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.requests > 0 && c.notModified == c.requests
+}
+
+// batchUnchanged reports whether every request since the previous call
+// returned 304, i.e. the batch streamed from them is already stored.
+func (c *httpCache) batchUnchanged() bool {
+	if c == nil {
+		return false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	requests := c.requests - c.batchStart[0]
+	notModified := c.notModified - c.batchStart[1]
+	c.batchStart = [2]int{c.requests, c.notModified}
+	return requests > 0 && notModified == requests
+}
+
+// store remembers a response until the cache is committed.
+func (c *httpCache) store(key string, response cachedResponse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.pending[key] = response
+}
+
+// lookup returns the stored response of a URL. Entries that cannot be read
+// are treated as missing, so the request is sent unconditionally.
+func (c *httpCache) lookup(key string) (cachedResponse, bool) {
+	record, err := c.findRecord(key)
+	if err != nil || record == nil {
+		return cachedResponse{}, false
+	}
+
+	fsys, err := c.app.NewFilesystem()
+	if err != nil {
+		return cachedResponse{}, false
+	}
+	defer func() { _ = fsys.Close() }()
+
+	r, err := fsys.GetReader(record.BaseFilesPath() + "/" + record.GetString("body"))
+	if err != nil {
+		log.Printf("Failed to read cached response of %s: %v", key, err)
+		return cachedResponse{}, false
+	}
+	defer func() { _ = r.Close() }()
+
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		log.Printf("Failed to read cached response of %s: %v", key, err)
+		return cachedResponse{}, false
+	}
+	defer func() { _ = zr.Close() }()
+
+	body, err := io.ReadAll(zr)
+	if err != nil {
+		log.Printf("Failed to read cached response of %s: %v", key, err)
+		return cachedResponse{}, false
+	}
+
+	return cachedResponse{
+		ETag:         record.GetString("etag"),
+		LastModified: record.GetString("last_modified"),
+		Body:         body,
+	}, true
+}
+
+// commit saves the responses received since the cache was created. Failures
+// are logged; the next sync then fetches the affected URLs in full.
+func (c *httpCache) commit() {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key, response := range c.pending {
+		if err := c.save(key, response); err != nil {
+			log.Printf("Failed to cache response of %s: %v", key, err)
+		}
+	}
+	c.pending = make(map[string]cachedResponse)
+}
+
+// save upserts the cache record of a URL.
+func (c *httpCache) save(key string, response cachedResponse) error {
+	record, err := c.findRecord(key)
+	if err != nil {
+		return err
+	}
+	if record == nil {
+		collection, err := c.app.FindCollectionByNameOrId(HTTPCacheCollection)
+		if err != nil {
+			return err
+		}
+		record = core.NewRecord(collection)
+		record.Set("url", key)
+	}
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(response.Body); err != nil {
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+	file, err := filesystem.NewFileFromBytes(buf.Bytes(), "body.gz")
+	if err != nil {
+		return err
+	}
+
+	record.Set("etag", response.ETag)
+	record.Set("last_modified", response.LastModified)
+	record.Set("body", file)
+	record.Set("stored_at", time.Now())
+	return c.app.Save(record)
+}
+
+// findRecord returns the cache record of a URL, or nil if there is none.
+func (c *httpCache) findRecord(key string) (*core.Record, error) {
+	records, err := c.app.FindRecordsByFilter(
+		HTTPCacheCollection,
+		"url = {:url}",
+		"",
+		1,
+		0,
+		map[string]any{"url": key},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("finding cached response: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+	return records[0], nil
+}
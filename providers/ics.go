@@ -0,0 +1,724 @@
+package providers
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ICSFeed describes a single iCalendar feed consumed by ICSProvider.
+type ICSFeed struct {
+	// URL is the address of the .ics file.
+	URL string
+	// SourceName is stored on every event coming from this feed.
+	SourceName string
+	// Category is used for all events of this feed.
+	Category string
+	// Location is used when an event has no LOCATION property.
+	Location string
+}
+
+// ICSProvider fetches events from one or more RFC 5545 iCalendar feeds.
+// Recurring events (RRULE) are expanded into individual occurrences.
+type ICSProvider struct {
+	// Name is the identifier returned by SourceName.
+	Name string
+	// Feeds is the list of calendars to fetch.
+	Feeds []ICSFeed
+	// Horizon limits how far into the future recurring events are expanded.
+	Horizon time.Duration
+	// MaxOccurrences caps the number of occurrences generated per recurring event.
+	MaxOccurrences int
+	// TimeZone is used for floating times, which carry no TZID and no Z.
+	TimeZone *time.Location
+	// Client is the HTTP client used for requests.
+	Client *http.Client
+}
+
+// NewICSProvider creates a new ICSProvider for the given feeds.
+func NewICSProvider(feeds ...ICSFeed) *ICSProvider {
+	return &ICSProvider{
+		Name:           "ics",
+		Feeds:          feeds,
+		Horizon:        180 * 24 * time.Hour,
+		MaxOccurrences: 100,
+		TimeZone:       time.UTC,
+		Client:         SharedClient,
+	}
+}
+
+// SourceName returns the unique identifier for this provider.
+func (p *ICSProvider) SourceName() string {
+	return p.Name
+}
+
+// FetchEvents downloads every configured feed and returns one raw event per
+// VEVENT occurrence. A failing feed is logged and skipped unless all feeds fail.
+func (p *ICSProvider) FetchEvents(ctx context.Context) ([]RawEvent, error) {
+	var events []RawEvent
+	var lastErr error
+	failed := 0
+
+	for _, feed := range p.Feeds {
+		feedEvents, err := p.fetchFeed(ctx, feed)
+		if err != nil {
+			log.Printf("ICS: failed to fetch feed %s: %v", feed.URL, err)
+			markPartial(ctx)
+			lastErr = err
+			failed++
+			continue
+		}
+		events = append(events, feedEvents...)
+	}
+
+	if failed > 0 && failed == len(p.Feeds) {
+		return nil, fmt.Errorf("all feeds failed: %w", lastErr)
+	}
+	return events, nil
+}
+
+// fetchFeed downloads and parses a single feed, following Link headers if
+// the server splits it.
+func (p *ICSProvider) fetchFeed(ctx context.Context, feed ICSFeed) ([]RawEvent, error) {
+	now := time.Now()
+	paginator := Paginator{Name: p.SourceName(), Strategy: PaginateLink}
+	return paginator.Fetch(ctx, p.Client, feed.URL, func(resp *http.Response, _ string) ([]RawEvent, string, error) {
+		vevents, err := parseICS(resp.Body)
+		if err != nil {
+			return nil, "", fmt.Errorf("parsing calendar: %w", err)
+		}
+
+		var events []RawEvent
+		for _, ve := range vevents {
+			events = append(events, p.expandVEvent(ve, feed, now)...)
+		}
+		return events, "", nil
+	})
+}
+
+// expandVEvent converts a parsed VEVENT into raw events, one per occurrence.
+func (p *ICSProvider) expandVEvent(ve icsComponent, feed ICSFeed, now time.Time) []RawEvent {
+	startProp, ok := ve.first("DTSTART")
+	if !ok {
+		return nil
+	}
+	start, allDay, err := parseICSTime(startProp, p.TimeZone)
+	if err != nil {
+		log.Printf("ICS: skipping event %q with invalid DTSTART: %v", ve.text("UID"), err)
+		return nil
+	}
+
+	var duration time.Duration
+	if endProp, ok := ve.first("DTEND"); ok {
+		if end, _, err := parseICSTime(endProp, p.TimeZone); err == nil && end.After(start) {
+			duration = end.Sub(start)
+		}
+	} else if durProp, ok := ve.first("DURATION"); ok {
+		if d, err := parseICSDuration(durProp.Value); err == nil {
+			duration = d
+		}
+	} else if allDay {
+		duration = 24 * time.Hour
+	}
+
+	uid := ve.text("UID")
+	if uid == "" {
+		hash := sha256.Sum256([]byte(ve.text("SUMMARY") + start.String()))
+		uid = hex.EncodeToString(hash[:8])
+	}
+
+	base := RawEvent{
+		"uid":         uid,
+		"summary":     ve.text("SUMMARY"),
+		"description": ve.text("DESCRIPTION"),
+		"location":    ve.text("LOCATION"),
+		"url":         ve.text("URL"),
+		"status":      strings.ToUpper(ve.text("STATUS")),
+		"all_day":     allDay,
+		"source_name": feed.SourceName,
+		"category":    feed.Category,
+		"feed_url":    feed.URL,
+		"default_loc": feed.Location,
+	}
+
+	var categories []any
+	for _, prop := range ve.all("CATEGORIES") {
+		for _, c := range splitICSList(prop.Value) {
+			if c = strings.TrimSpace(c); c != "" {
+				categories = append(categories, c)
+			}
+		}
+	}
+	base["categories"] = categories
+
+	if geo, ok := ve.first("GEO"); ok {
+		parts := strings.Split(geo.Value, ";")
+		if len(parts) == 2 {
+			lat, errLat := strconv.ParseFloat(strings.TrimSpace(parts[0]), 64)
+			long, errLong := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+			if errLat == nil && errLong == nil {
+				base["latitude"] = lat
+				base["longitude"] = long
+			}
+		}
+	}
+
+	rruleProp, recurring := ve.first("RRULE")
+	if !recurring {
+		raw := copyRaw(base)
+		raw["dtstart"] = start.Format(time.RFC3339)
+		raw["dtend"] = start.Add(duration).Format(time.RFC3339)
+		return []RawEvent{raw}
+	}
+
+	rule, err := parseRRule(rruleProp.Value, start.Location())
+	if err != nil {
+		log.Printf("ICS: unsupported RRULE for %q, using first occurrence: %v", uid, err)
+		rule = rrule{freq: "", interval: 1, count: 1}
+	}
+
+	excluded := make(map[int64]bool)
+	for _, prop := range ve.all("EXDATE") {
+		for _, v := range strings.Split(prop.Value, ",") {
+			if t, _, err := parseICSTime(icsProperty{Name: "EXDATE", Params: prop.Params, Value: v}, start.Location()); err == nil {
+				excluded[t.Unix()] = true
+			}
+		}
+	}
+
+	windowStart := now
+	if start.After(windowStart) {
+		windowStart = start
+	}
+	windowEnd := windowStart.Add(p.Horizon)
+
+	var events []RawEvent
+	for _, occ := range rule.occurrences(start, windowEnd) {
+		if len(events) >= p.MaxOccurrences {
+			break
+		}
+		if excluded[occ.Unix()] || occ.Add(duration).Before(now) {
+			continue
+		}
+		raw := copyRaw(base)
+		raw["uid"] = uid + "-" + occ.UTC().Format("20060102T150405")
+		raw["dtstart"] = occ.Format(time.RFC3339)
+		raw["dtend"] = occ.Add(duration).Format(time.RFC3339)
+		events = append(events, raw)
+	}
+	return events
+}
+
+// MapEvent converts a RawEvent into the internal Event structure.
+func (p *ICSProvider) MapEvent(raw RawEvent) *Event {
+	uid, _ := raw["uid"].(string)
+	title, _ := raw["summary"].(string)
+	if uid == "" || title == "" {
+		return nil
+	}
+
+	dateStart, err := time.Parse(time.RFC3339, fmt.Sprint(raw["dtstart"]))
+	if err != nil {
+		return nil
+	}
+	dateEnd, err := time.Parse(time.RFC3339, fmt.Sprint(raw["dtend"]))
+	if err != nil || dateEnd.Before(dateStart) {
+		dateEnd = dateStart
+	}
+
+	sourceName, _ := raw["source_name"].(string)
+	if sourceName == "" {
+		sourceName = p.SourceName()
+	}
+
+	category, _ := raw["category"].(string)
+	if category == "" {
+		category = "general"
+	}
+
+	location, _ := raw["location"].(string)
+	if location == "" {
+		location, _ = raw["default_loc"].(string)
+	}
+
+	link, _ := raw["url"].(string)
+	if link == "" {
+		link, _ = raw["feed_url"].(string)
+	}
+
+	description, _ := raw["description"].(string)
+	lat, _ := raw["latitude"].(float64)
+	long, _ := raw["longitude"].(float64)
+
+	// Tentative and confirmed events are left to resolveStatus
+	status := ""
+	if s, _ := raw["status"].(string); s == "CANCELLED" {
+		status = EventCancelled
+	}
+
+	topics := []string{}
+	if cats, ok := raw["categories"].([]any); ok {
+		for _, c := range cats {
+			if s, ok := c.(string); ok {
+				topics = append(topics, s)
+			}
+		}
+	}
+
+	return &Event{
+		ID:          uid,
+		Title:       title,
+		Description: description,
+		DateStart:   dateStart,
+		DateEnd:     dateEnd,
+		Location:    location,
+		URL:         link,
+		SourceName:  sourceName,
+		SourceID:    uid,
+		Topics:      topics,
+		Category:    category,
+		Status:      status,
+		Latitude:    lat,
+		Longitude:   long,
+	}
+}
+
+// copyRaw returns a shallow copy of a raw event.
+func copyRaw(raw RawEvent) RawEvent {
+	c := make(RawEvent, len(raw)+2)
+	for k, v := range raw {
+		c[k] = v
+	}
+	return c
+}
+
+// icsProperty is a single content line of an iCalendar object.
+type icsProperty struct {
+	Name   string
+	Params map[string]string
+	Value  string
+}
+
+// icsComponent holds the properties of a single VEVENT.
+type icsComponent struct {
+	props []icsProperty
+}
+
+// first returns the first property with the given name.
+func (c icsComponent) first(name string) (icsProperty, bool) {
+	for _, p := range c.props {
+		if p.Name == name {
+			return p, true
+		}
+	}
+	return icsProperty{}, false
+}
+
+// all returns every property with the given name.
+func (c icsComponent) all(name string) []icsProperty {
+	var props []icsProperty
+	for _, p := range c.props {
+		if p.Name == name {
+			props = append(props, p)
+		}
+	}
+	return props
+}
+
+// text returns the unescaped value of the first property with the given name.
+func (c icsComponent) text(name string) string {
+	p, ok := c.first(name)
+	if !ok {
+		return ""
+	}
+	return unescapeICSText(p.Value)
+}
+
+// parseICS reads an iCalendar stream and returns its top-level VEVENTs.
+// Properties of nested components such as VALARM are ignored.
+func parseICS(r io.Reader) ([]icsComponent, error) {
+	lines, err := unfoldICSLines(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []icsComponent
+	var current *icsComponent
+	var stack []string
+
+	for _, line := range lines {
+		prop, ok := parseICSLine(line)
+		if !ok {
+			continue
+		}
+
+		switch prop.Name {
+		case "BEGIN":
+			stack = append(stack, strings.ToUpper(prop.Value))
+			if stack[len(stack)-1] == "VEVENT" && current == nil {
+				current = &icsComponent{}
+			}
+			continue
+		case "END":
+			if len(stack) > 0 {
+				if stack[len(stack)-1] == "VEVENT" && current != nil {
+					events = append(events, *current)
+					current = nil
+				}
+				stack = stack[:len(stack)-1]
+			}
+			continue
+		}
+
+		if current != nil && len(stack) > 0 && stack[len(stack)-1] == "VEVENT" {
+			current.props = append(current.props, prop)
+		}
+	}
+
+	return events, nil
+}
+
+// unfoldICSLines splits the input into logical lines, joining folded
+// continuation lines that start with a space or a tab.
+func unfoldICSLines(r io.Reader) ([]string, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	var lines []string
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading lines: %w", err)
+	}
+	return lines, nil
+}
+
+// parseICSLine parses "NAME;PARAM=VALUE:value" into an icsProperty.
+func parseICSLine(line string) (icsProperty, bool) {
+	if line == "" {
+		return icsProperty{}, false
+	}
+
+	// Find the first colon that is not inside a quoted parameter value.
+	inQuotes := false
+	sep := -1
+	for i, c := range line {
+		if c == '"' {
+			inQuotes = !inQuotes
+		} else if c == ':' && !inQuotes {
+			sep = i
+			break
+		}
+	}
+	if sep < 0 {
+		return icsProperty{}, false
+	}
+
+	head, value := line[:sep], line[sep+1:]
+	parts := strings.Split(head, ";")
+	name := strings.ToUpper(parts[0])
+	// Strip optional group prefix, e.g. "item1.URL".
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		name = name[i+1:]
+	}
+
+	params := make(map[string]string)
+	for _, param := range parts[1:] {
+		k, v, ok := strings.Cut(param, "=")
+		if !ok {
+			continue
+		}
+		params[strings.ToUpper(k)] = strings.Trim(v, `"`)
+	}
+
+	return icsProperty{Name: name, Params: params, Value: value}, true
+}
+
+// unescapeICSText decodes the TEXT value escapes defined by RFC 5545.
+func unescapeICSText(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) {
+			i++
+			switch s[i] {
+			case 'n', 'N':
+				b.WriteByte('\n')
+			default:
+				b.WriteByte(s[i])
+			}
+			continue
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+// splitICSList splits a comma separated TEXT list, honoring escaped commas.
+func splitICSList(s string) []string {
+	var items []string
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '\\' && i+1 < len(s):
+			b.WriteByte(s[i])
+			b.WriteByte(s[i+1])
+			i++
+		case s[i] == ',':
+			items = append(items, unescapeICSText(b.String()))
+			b.Reset()
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return append(items, unescapeICSText(b.String()))
+}
+
+// parseICSTime parses a DATE or DATE-TIME property, honoring TZID.
+// Floating times without a zone are interpreted in floating, or UTC if nil.
+func parseICSTime(prop icsProperty, floating *time.Location) (time.Time, bool, error) {
+	value := strings.TrimSpace(prop.Value)
+
+	loc := floating
+	if loc == nil {
+		loc = time.UTC
+	}
+	if tzid := prop.Params["TZID"]; tzid != "" {
+		if l, err := time.LoadLocation(tzid); err == nil {
+			loc = l
+		} else {
+			log.Printf("ICS: unknown TZID %q, treating as floating time", tzid)
+		}
+	}
+
+	if prop.Params["VALUE"] == "DATE" || len(value) == 8 {
+		t, err := time.ParseInLocation("20060102", value, loc)
+		return t, true, err
+	}
+	if strings.HasSuffix(value, "Z") {
+		t, err := time.Parse("20060102T150405Z", value)
+		return t, false, err
+	}
+	t, err := time.ParseInLocation("20060102T150405", value, loc)
+	return t, false, err
+}
+
+// parseICSDuration parses an RFC 5545 DURATION value such as "PT1H30M" or "P2D".
+func parseICSDuration(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, fmt.Errorf("empty duration")
+	}
+
+	sign := time.Duration(1)
+	switch s[0] {
+	case '-':
+		sign = -1
+		s = s[1:]
+	case '+':
+		s = s[1:]
+	}
+	if !strings.HasPrefix(s, "P") {
+		return 0, fmt.Errorf("malformed duration %q", s)
+	}
+	s = s[1:]
+
+	var total time.Duration
+	inTime := false
+	num := ""
+	for _, c := range s {
+		switch {
+		case c == 'T':
+			inTime = true
+		case c >= '0' && c <= '9':
+			num += string(c)
+		default:
+			n, err := strconv.Atoi(num)
+			if err != nil {
+				return 0, fmt.Errorf("malformed duration %q", s)
+			}
+			num = ""
+			unit := map[rune]time.Duration{
+				'W': 7 * 24 * time.Hour,
+				'D': 24 * time.Hour,
+			}
+			if inTime {
+				unit = map[rune]time.Duration{
+					'H': time.Hour,
+					'M': time.Minute,
+					'S': time.Second,
+				}
+			}
+			d, ok := unit[c]
+			if !ok {
+				return 0, fmt.Errorf("malformed duration %q", s)
+			}
+			total += time.Duration(n) * d
+		}
+	}
+	return sign * total, nil
+}
+
+// rrule is the supported subset of an RFC 5545 recurrence rule.
+type rrule struct {
+	freq     string
+	interval int
+	count    int
+	until    time.Time
+	byDay    []time.Weekday
+}
+
+// parseRRule parses an RRULE value. Supported frequencies are DAILY, WEEKLY,
+// MONTHLY and YEARLY with INTERVAL, COUNT, UNTIL and (weekly) BYDAY.
+func parseRRule(value string, loc *time.Location) (rrule, error) {
+	rule := rrule{interval: 1}
+	weekdays := map[string]time.Weekday{
+		"SU": time.Sunday, "MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday,
+		"TH": time.Thursday, "FR": time.Friday, "SA": time.Saturday,
+	}
+
+	for _, part := range strings.Split(value, ";") {
+		k, v, ok := strings.Cut(part, "=")
+		if !ok {
+			continue
+		}
+		switch strings.ToUpper(k) {
+		case "FREQ":
+			rule.freq = strings.ToUpper(v)
+		case "INTERVAL":
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 1 {
+				return rule, fmt.Errorf("invalid INTERVAL %q", v)
+			}
+			rule.interval = n
+		case "COUNT":
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 1 {
+				return rule, fmt.Errorf("invalid COUNT %q", v)
+			}
+			rule.count = n
+		case "UNTIL":
+			t, _, err := parseICSTime(icsProperty{Value: v, Params: map[string]string{}}, loc)
+			if err != nil {
+				return rule, fmt.Errorf("invalid UNTIL %q: %w", v, err)
+			}
+			if !strings.HasSuffix(v, "Z") {
+				t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, loc)
+			}
+			if len(v) == 8 {
+				// A date-only UNTIL includes the whole day.
+				t = t.Add(24*time.Hour - time.Second)
+			}
+			rule.until = t
+		case "BYDAY":
+			for _, d := range strings.Split(v, ",") {
+				wd, ok := weekdays[strings.ToUpper(d)]
+				if !ok {
+					return rule, fmt.Errorf("unsupported BYDAY %q", d)
+				}
+				rule.byDay = append(rule.byDay, wd)
+			}
+		}
+	}
+
+	// Emit weekly occurrences in calendar order (weeks start on Monday).
+	sort.Slice(rule.byDay, func(i, j int) bool {
+		return (rule.byDay[i]+6)%7 < (rule.byDay[j]+6)%7
+	})
+
+	switch rule.freq {
+	case "DAILY", "WEEKLY", "MONTHLY", "YEARLY":
+	default:
+		return rule, fmt.Errorf("unsupported FREQ %q", rule.freq)
+	}
+	if len(rule.byDay) > 0 && rule.freq != "WEEKLY" {
+		return rule, fmt.Errorf("BYDAY is only supported with FREQ=WEEKLY")
+	}
+	return rule, nil
+}
+
+// occurrences lists the start times generated by the rule, beginning at start
+// and stopping at COUNT, UNTIL or windowEnd, whichever comes first.
+func (r rrule) occurrences(start, windowEnd time.Time) []time.Time {
+	var result []time.Time
+	emitted := 0
+
+	emit := func(t time.Time) bool {
+		if t.Before(start) {
+			return true
+		}
+		if (r.count > 0 && emitted >= r.count) ||
+			(!r.until.IsZero() && t.After(r.until)) ||
+			t.After(windowEnd) {
+			return false
+		}
+		emitted++
+		result = append(result, t)
+		return true
+	}
+
+	if r.freq == "" {
+		emit(start)
+		return result
+	}
+
+	// Guard against runaway loops for malformed rules.
+	const maxIterations = 10000
+
+	if r.freq == "WEEKLY" && len(r.byDay) > 0 {
+		// Align to the Monday of the start week, then visit each BYDAY.
+		offset := (int(start.Weekday()) + 6) % 7
+		weekStart := start.AddDate(0, 0, -offset)
+		for i := 0; i < maxIterations; i++ {
+			week := weekStart.AddDate(0, 0, 7*r.interval*i)
+			for _, wd := range r.byDay {
+				day := week.AddDate(0, 0, (int(wd)+6)%7)
+				if !emit(day) {
+					return result
+				}
+			}
+		}
+		return result
+	}
+
+	for i := 0; i < maxIterations; i++ {
+		var t time.Time
+		switch r.freq {
+		case "DAILY":
+			t = start.AddDate(0, 0, r.interval*i)
+		case "WEEKLY":
+			t = start.AddDate(0, 0, 7*r.interval*i)
+		case "MONTHLY":
+			t = start.AddDate(0, r.interval*i, 0)
+			if t.Day() != start.Day() {
+				continue // e.g. the 31st in a 30-day month
+			}
+		case "YEARLY":
+			t = start.AddDate(r.interval*i, 0, 0)
+			if t.Day() != start.Day() {
+				continue
+			}
+		}
+		if !emit(t) {
+			return result
+		}
+	}
+	return result
+}
@@ -0,0 +1,133 @@
+package providers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestICSProvider_FetchEvents(t *testing.T) {
+	fixture, err := os.ReadFile("fixtures/calendar.ics")
+	require.NoError(t, err)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/calendar.ics", r.URL.Path)
+		w.Header().Set("Content-Type", "text/calendar")
+		_, _ = w.Write(fixture)
+	}))
+	defer server.Close()
+
+	p := NewICSProvider(ICSFeed{
+		URL:        server.URL + "/calendar.ics",
+		SourceName: "test_calendar",
+		Category:   "Community",
+		Location:   "Bolzano",
+	})
+	// The fixture lives in 2030; make sure the whole recurrence fits the window.
+	p.Horizon = 10 * 365 * 24 * time.Hour
+
+	events, err := p.FetchEvents(context.Background())
+	require.NoError(t, err)
+
+	var mapped []*Event
+	for _, raw := range events {
+		if ev := p.MapEvent(raw); ev != nil {
+			mapped = append(mapped, ev)
+		}
+	}
+	// 1 timed event + 1 all-day event + 3 meetup occurrences (4 minus one EXDATE)
+	// + 1 cancelled event.
+	require.Len(t, mapped, 6)
+	assert.Equal(t, EventCancelled, mapped[5].Status)
+	assert.Empty(t, mapped[0].Status)
+
+	talk := mapped[0]
+	assert.Equal(t, "talk-1@example.com", talk.SourceID)
+	assert.Equal(t, "Open Source Evening, Bolzano", talk.Title)
+	assert.Equal(t, "Talks and pizza.\nBring your laptop.", talk.Description)
+	assert.Equal(t, "NOI Techpark, Bolzano", talk.Location)
+	assert.Equal(t, "test_calendar", talk.SourceName)
+	assert.Equal(t, "Community", talk.Category)
+	assert.Equal(t, []string{"Tech", "Community"}, talk.Topics)
+	assert.InDelta(t, 46.4786, talk.Latitude, 0.0001)
+	assert.InDelta(t, 11.3317, talk.Longitude, 0.0001)
+	assert.Equal(t, time.Date(2030, 2, 10, 17, 0, 0, 0, time.UTC), talk.DateStart.UTC())
+	assert.Equal(t, 2*time.Hour, talk.DateEnd.Sub(talk.DateStart))
+
+	fair := mapped[1]
+	assert.Equal(t, "Spring Fair", fair.Title)
+	assert.Contains(t, fair.Description, "folded across two lines")
+	assert.Equal(t, "Bolzano", fair.Location)
+	assert.Equal(t, server.URL+"/calendar.ics", fair.URL)
+	assert.Equal(t, 24*time.Hour, fair.DateEnd.Sub(fair.DateStart))
+
+	var meetupStarts []time.Time
+	for _, ev := range mapped[2:5] {
+		assert.Equal(t, "Weekly Go Meetup", ev.Title)
+		assert.Equal(t, 90*time.Minute, ev.DateEnd.Sub(ev.DateStart))
+		meetupStarts = append(meetupStarts, ev.DateStart.UTC())
+	}
+	assert.Equal(t, []time.Time{
+		time.Date(2030, 1, 7, 17, 0, 0, 0, time.UTC),
+		time.Date(2030, 1, 14, 17, 0, 0, 0, time.UTC),
+		time.Date(2030, 1, 16, 17, 0, 0, 0, time.UTC),
+	}, meetupStarts)
+	assert.Equal(t, "meetup-1@example.com-20300114T170000", mapped[3].SourceID)
+}
+
+func TestICSProvider_FetchEvents_PartialFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/broken.ics" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		_, _ = w.Write([]byte("BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nUID:a\r\nDTSTART:20300101T100000Z\r\nSUMMARY:A\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n"))
+	}))
+	defer server.Close()
+
+	p := NewICSProvider(
+		ICSFeed{URL: server.URL + "/ok.ics", SourceName: "ok"},
+		ICSFeed{URL: server.URL + "/broken.ics", SourceName: "broken"},
+	)
+	events, err := p.FetchEvents(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, events, 1)
+
+	p.Feeds = p.Feeds[1:]
+	_, err = p.FetchEvents(context.Background())
+	assert.Error(t, err)
+}
+
+func TestICSProvider_FloatingTimes(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("BEGIN:VCALENDAR\r\n" +
+			"BEGIN:VEVENT\r\nUID:floating\r\nDTSTART:20300701T210000\r\nDTEND:20300701T230000\r\nSUMMARY:Concert\r\nEND:VEVENT\r\n" +
+			"BEGIN:VEVENT\r\nUID:utc\r\nDTSTART:20300701T210000Z\r\nSUMMARY:Talk\r\nEND:VEVENT\r\n" +
+			"END:VCALENDAR\r\n"))
+	}))
+	defer server.Close()
+
+	rome, err := time.LoadLocation("Europe/Rome")
+	require.NoError(t, err)
+	p := NewICSProvider(ICSFeed{URL: server.URL, SourceName: "city"})
+	p.TimeZone = rome
+
+	events, err := p.FetchEvents(context.Background())
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+
+	// 21:00 in Bolzano is 19:00 UTC in summer; UTC times are unaffected
+	concert := p.MapEvent(events[0])
+	require.NotNil(t, concert)
+	assert.Equal(t, time.Date(2030, 7, 1, 19, 0, 0, 0, time.UTC), concert.DateStart.UTC())
+	assert.Equal(t, 2*time.Hour, concert.DateEnd.Sub(concert.DateStart))
+	talk := p.MapEvent(events[1])
+	require.NotNil(t, talk)
+	assert.Equal(t, time.Date(2030, 7, 1, 21, 0, 0, 0, time.UTC), talk.DateStart.UTC())
+}
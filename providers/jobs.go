@@ -0,0 +1,389 @@
+package providers
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/security"
+)
+
+// Sync job states.
+const (
+	SyncJobQueued    = "queued"
+	SyncJobRunning   = "running"
+	SyncJobCompleted = "completed"
+	SyncJobCancelled = "cancelled"
+)
+
+// Provider states within a sync job.
+const (
+	ProviderPending   = "pending"
+	ProviderFetching  = "fetching"
+	ProviderWriting   = "writing"
+	ProviderDone      = "done"
+	ProviderDegraded  = "degraded"
+	ProviderFailed    = "failed"
+	ProviderTimedOut  = "timeout"
+	ProviderCancelled = "cancelled"
+)
+
+// maxFinishedSyncJobs is the number of finished jobs kept for inspection.
+const maxFinishedSyncJobs = 20
+
+// ProviderProgress is the progress of one provider within a sync job.
+type ProviderProgress struct {
+	State string    `json:"state"`
+	Stats SyncStats `json:"stats"`
+}
+
+// SyncJob is a sync running in the background. Its methods are safe for
+// concurrent use.
+type SyncJob struct {
+	// ID identifies the job in the API.
+	ID string
+	// Full makes incremental providers fetch their whole catalogue.
+	Full bool
+
+	mu         sync.Mutex
+	status     string
+	startedAt  time.Time
+	finishedAt time.Time
+	order      []string
+	providers  map[string]*ProviderProgress
+	active     []activeProvider
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// SyncJobSnapshot is a point-in-time copy of a SyncJob.
+type SyncJobSnapshot struct {
+	ID         string             `json:"id"`
+	Full       bool               `json:"full"`
+	Status     string             `json:"status"`
+	StartedAt  time.Time          `json:"started_at"`
+	FinishedAt *time.Time         `json:"finished_at"`
+	Providers  []ProviderProgress `json:"providers"`
+	// Completed is the number of providers that are no longer running.
+	Completed int `json:"completed"`
+	// Total sums the stats of all providers.
+	Total SyncStats `json:"total"`
+}
+
+// Finished reports whether the job is no longer queued or running.
+func (s SyncJobSnapshot) Finished() bool {
+	return s.Status != SyncJobQueued && s.Status != SyncJobRunning
+}
+
+// syncJobs holds the queued, running and recently finished jobs of this
+// process. At most one job runs at a time; providers requested meanwhile are
+// collected in a single queued job, which starts when the running one ends.
+var syncJobs = struct {
+	sync.Mutex
+	byID    map[string]*SyncJob
+	order   []string
+	running *SyncJob
+	queued  *SyncJob
+}{byID: make(map[string]*SyncJob)}
+
+// StartSyncJob starts a sync of all active providers in the background and
+// returns immediately. If this process is already syncing all of them, the
+// running job is returned and joined is true. If it is syncing only some of
+// them, the sync is queued behind it. If another process holds the sync
+// lock, the error is a *SyncLockedError.
+func StartSyncJob(app core.App) (job *SyncJob, joined bool, err error) {
+	active, err := loadActiveProviders(app)
+	if err != nil {
+		return nil, false, err
+	}
+	return startSyncJob(app, active, false)
+}
+
+// StartFullSyncJob is like StartSyncJob, but incremental providers ignore
+// their cursor and fetch everything. A joined job may be incremental.
+func StartFullSyncJob(app core.App) (job *SyncJob, joined bool, err error) {
+	active, err := loadActiveProviders(app)
+	if err != nil {
+		return nil, false, err
+	}
+	return startSyncJob(app, active, true)
+}
+
+// startSyncJob starts a job syncing the given providers. A running job is
+// joined only if it syncs all of them; otherwise the providers are added to
+// the queued job, which is created if needed, so that a sync requested while
+// another one runs is never dropped or cut short.
+func startSyncJob(app core.App, active []activeProvider, full bool) (*SyncJob, bool, error) {
+	syncJobs.Lock()
+	defer syncJobs.Unlock()
+
+	if running := syncJobs.running; running != nil {
+		if running.covers(active) {
+			return running, true, nil
+		}
+		if queued := syncJobs.queued; queued != nil {
+			queued.add(active, full)
+			return queued, true, nil
+		}
+		job := newQueuedSyncJob(active, full)
+		registerSyncJob(job)
+		syncJobs.queued = job
+		return job, false, nil
+	}
+
+	job := newQueuedSyncJob(active, full)
+	if err := acquireSyncLock(app, job.ID); err != nil {
+		job.cancel()
+		return nil, false, err
+	}
+	registerSyncJob(job)
+	runSyncJob(app, job)
+	return job, false, nil
+}
+
+// newQueuedSyncJob creates a job for the given providers that has not
+// started yet.
+func newQueuedSyncJob(active []activeProvider, full bool) *SyncJob {
+	ctx, cancel := context.WithCancel(context.Background())
+	job := newSyncJob(active, cancel)
+	job.Full = full
+	job.status = SyncJobQueued
+	job.active = active
+	job.ctx = ctx
+	return job
+}
+
+// runSyncJob runs a queued job in the background. The caller must hold
+// syncJobs and the sync lock for the job. When the job ends, the job queued
+// meanwhile takes over the lock and runs next.
+func runSyncJob(app core.App, job *SyncJob) {
+	job.mu.Lock()
+	job.status = SyncJobRunning
+	job.startedAt = time.Now()
+	active := job.active
+	job.mu.Unlock()
+
+	ctx, cancel := job.ctx, job.cancel
+	syncJobs.running = job
+
+	go func() {
+		defer cancel()
+
+		// Keep the lock alive while the job runs
+		heartbeat := time.NewTicker(SyncLockTTL / 3)
+		defer heartbeat.Stop()
+		go func() {
+			for {
+				select {
+				case <-heartbeat.C:
+					refreshSyncLock(app, job.ID)
+				case <-job.done:
+					return
+				}
+			}
+		}()
+
+		syncProviders(ctx, app, active, job)
+
+		releaseSyncLock(app, job.ID)
+		syncJobs.Lock()
+		syncJobs.running = nil
+		next := syncJobs.queued
+		syncJobs.queued = nil
+		if next != nil {
+			if err := acquireSyncLock(app, next.ID); err != nil {
+				log.Printf("Dropping queued sync job %s: %v", next.ID, err)
+				next.cancel()
+				next.finish(true)
+			} else {
+				runSyncJob(app, next)
+			}
+		}
+		syncJobs.Unlock()
+
+		job.finish(ctx.Err() != nil)
+	}()
+}
+
+// newSyncJob creates a job for the given providers, all pending.
+func newSyncJob(active []activeProvider, cancel context.CancelFunc) *SyncJob {
+	job := &SyncJob{
+		ID:        security.RandomString(15),
+		status:    SyncJobRunning,
+		startedAt: time.Now(),
+		providers: make(map[string]*ProviderProgress, len(active)),
+		cancel:    cancel,
+		done:      make(chan struct{}),
+	}
+	for _, p := range active {
+		job.order = append(job.order, p.SourceName())
+		job.providers[p.SourceName()] = &ProviderProgress{
+			State: ProviderPending,
+			Stats: SyncStats{Provider: p.SourceName()},
+		}
+	}
+	return job
+}
+
+// FindSyncJob returns a running or recently finished job by ID.
+func FindSyncJob(id string) (*SyncJob, bool) {
+	syncJobs.Lock()
+	defer syncJobs.Unlock()
+
+	job, ok := syncJobs.byID[id]
+	return job, ok
+}
+
+// RunningSyncJob returns the job currently running in this process, if any.
+func RunningSyncJob() (*SyncJob, bool) {
+	syncJobs.Lock()
+	defer syncJobs.Unlock()
+
+	return syncJobs.running, syncJobs.running != nil
+}
+
+// registerSyncJob stores a job and forgets the oldest finished ones.
+// The caller must hold syncJobs.
+func registerSyncJob(job *SyncJob) {
+	syncJobs.byID[job.ID] = job
+	syncJobs.order = append(syncJobs.order, job.ID)
+
+	finished := 0
+	for i := len(syncJobs.order) - 1; i >= 0; i-- {
+		id := syncJobs.order[i]
+		if !syncJobs.byID[id].Snapshot().Finished() {
+			continue
+		}
+		finished++
+		if finished > maxFinishedSyncJobs {
+			delete(syncJobs.byID, id)
+			syncJobs.order = append(syncJobs.order[:i], syncJobs.order[i+1:]...)
+		}
+	}
+}
+
+// Cancel stops the job. Providers that are still fetching are aborted and
+// no further events are written. A queued job is dropped without running.
+func (j *SyncJob) Cancel() {
+	j.cancel()
+
+	syncJobs.Lock()
+	defer syncJobs.Unlock()
+	if syncJobs.queued == j {
+		syncJobs.queued = nil
+		j.finish(true)
+	}
+}
+
+// covers reports whether the job syncs all the given providers.
+func (j *SyncJob) covers(active []activeProvider) bool {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	for _, p := range active {
+		if _, ok := j.providers[p.SourceName()]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// add adds the providers the queued job does not sync yet. A full sync
+// request makes the whole job full.
+func (j *SyncJob) add(active []activeProvider, full bool) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.Full = j.Full || full
+	for _, p := range active {
+		if _, ok := j.providers[p.SourceName()]; ok {
+			continue
+		}
+		j.order = append(j.order, p.SourceName())
+		j.providers[p.SourceName()] = &ProviderProgress{
+			State: ProviderPending,
+			Stats: SyncStats{Provider: p.SourceName()},
+		}
+		j.active = append(j.active, p)
+	}
+}
+
+// Done is closed when the job has finished.
+func (j *SyncJob) Done() <-chan struct{} {
+	return j.done
+}
+
+// Wait blocks until the job has finished and returns the stats per provider.
+func (j *SyncJob) Wait() map[string]SyncStats {
+	<-j.done
+
+	snapshot := j.Snapshot()
+	stats := make(map[string]SyncStats, len(snapshot.Providers))
+	for _, p := range snapshot.Providers {
+		stats[p.Stats.Provider] = p.Stats
+	}
+	return stats
+}
+
+// Snapshot returns the current state of the job.
+func (j *SyncJob) Snapshot() SyncJobSnapshot {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	s := SyncJobSnapshot{
+		ID:        j.ID,
+		Full:      j.Full,
+		Status:    j.status,
+		StartedAt: j.startedAt,
+		Providers: make([]ProviderProgress, 0, len(j.order)),
+	}
+	if !j.finishedAt.IsZero() {
+		finishedAt := j.finishedAt
+		s.FinishedAt = &finishedAt
+	}
+	for _, name := range j.order {
+		p := *j.providers[name]
+		s.Providers = append(s.Providers, p)
+		if p.State != ProviderPending && p.State != ProviderFetching && p.State != ProviderWriting {
+			s.Completed++
+		}
+		s.Total.New += p.Stats.New
+		s.Total.Updated += p.Stats.Updated
+		s.Total.Unchanged += p.Stats.Unchanged
+		s.Total.Removed += p.Stats.Removed
+		s.Total.Errors += p.Stats.Errors
+		s.Total.Timeouts += p.Stats.Timeouts
+		s.Total.Fetched += p.Stats.Fetched
+		s.Total.Mapped += p.Stats.Mapped
+		s.Total.Skipped += p.Stats.Skipped
+	}
+	return s
+}
+
+// setState records the state and stats of a provider.
+func (j *SyncJob) setState(state string, stats SyncStats) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if p, ok := j.providers[stats.Provider]; ok {
+		p.State = state
+		p.Stats = stats
+	}
+}
+
+// finish marks the job as completed or cancelled.
+func (j *SyncJob) finish(cancelled bool) {
+	j.mu.Lock()
+	j.status = SyncJobCompleted
+	if cancelled {
+		j.status = SyncJobCancelled
+	}
+	j.finishedAt = time.Now()
+	j.mu.Unlock()
+
+	close(j.done)
+}
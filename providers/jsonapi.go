@@ -0,0 +1,508 @@
+package providers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"html"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// JSONAPIDefinition declares how to read events from a JSON REST endpoint.
+type JSONAPIDefinition struct {
+	// SourceName is the unique identifier of the source.
+	SourceName string `json:"source_name"`
+	// URL is the endpoint to GET.
+	URL string `json:"url"`
+	// Query holds query parameters. The placeholder {today} is replaced with
+	// the current date (YYYY-MM-DD).
+	Query map[string]string `json:"query,omitempty"`
+	// Headers holds extra request headers, e.g. an API key.
+	Headers map[string]string `json:"headers,omitempty"`
+	// ItemsPath locates the array of events in the response, e.g. "Items"
+	// or "data.events". An empty path means the response itself is the array.
+	ItemsPath string `json:"items_path,omitempty"`
+	// Fields maps event fields (id, title, description, date_start, date_end,
+	// location, url, image, latitude, longitude, topics, category) to paths.
+	Fields map[string]FieldMapping `json:"fields"`
+	// DateLayouts are Go time layouts tried before the common formats.
+	DateLayouts []string `json:"date_layouts,omitempty"`
+	// TimeZone is the IANA zone of dates without zone information (default UTC).
+	TimeZone string `json:"time_zone,omitempty"`
+	// IDPrefix is prepended to the source ID.
+	IDPrefix string `json:"id_prefix,omitempty"`
+	// Location is used when the location field is missing or empty.
+	Location string `json:"location,omitempty"`
+	// Category is used when the category field is missing or empty.
+	Category string `json:"category,omitempty"`
+	// DefaultDuration is used when no end date is found (default 2h).
+	DefaultDuration string `json:"default_duration,omitempty"`
+	// Pagination declares how to fetch further pages. Without it, only the
+	// URL itself is fetched.
+	Pagination *JSONAPIPagination `json:"pagination,omitempty"`
+}
+
+// JSONAPIPagination declares how to walk the pages of a JSON API, see Paginator.
+type JSONAPIPagination struct {
+	// Type is "page", "offset", "cursor" or "link".
+	Type string `json:"type"`
+	// Param is the query parameter of the page number, offset or cursor.
+	Param string `json:"param,omitempty"`
+	// SizeParam is the query parameter of the page size.
+	SizeParam string `json:"size_param,omitempty"`
+	// PageSize is the number of items requested per page.
+	PageSize int `json:"page_size,omitempty"`
+	// Start is the first page number or offset.
+	Start int `json:"start,omitempty"`
+	// CursorPath locates the cursor, or the URL of the next page, in each
+	// response (cursor pagination).
+	CursorPath string `json:"cursor_path,omitempty"`
+	// MaxPages caps the pages fetched.
+	MaxPages int `json:"max_pages,omitempty"`
+	// MaxItems caps the items fetched.
+	MaxItems int `json:"max_items,omitempty"`
+}
+
+// FieldMapping describes how to read one event field from an item.
+//
+// A path is a dot separated list of keys with optional array indexes, e.g.
+// "ImageGallery[0].ImageUrl". Alternatives in braces are tried in order, so
+// "Detail.{en,it,de}.Title" reads the English title and falls back to Italian
+// and German. In JSON a mapping can be written as a single path string, as a
+// list of fallback paths, or as an object.
+type FieldMapping struct {
+	// Paths are tried in order; the first non-empty value wins.
+	Paths []string `json:"paths,omitempty"`
+	// Join, if set, combines all non-empty path values with this separator
+	// instead of picking the first one.
+	Join string `json:"join,omitempty"`
+	// Template builds the value from placeholders such as "{{Id}}". The
+	// result is empty if any placeholder resolves to an empty value.
+	Template string `json:"template,omitempty"`
+	// Default is used when nothing else yields a value.
+	Default string `json:"default,omitempty"`
+}
+
+// UnmarshalJSON accepts a path string, a list of paths, or a full object.
+func (m *FieldMapping) UnmarshalJSON(data []byte) error {
+	var path string
+	if err := json.Unmarshal(data, &path); err == nil {
+		m.Paths = []string{path}
+		return nil
+	}
+	var paths []string
+	if err := json.Unmarshal(data, &paths); err == nil {
+		m.Paths = paths
+		return nil
+	}
+	type plain FieldMapping
+	var full plain
+	if err := json.Unmarshal(data, &full); err != nil {
+		return fmt.Errorf("field mapping must be a path, a list of paths or an object: %w", err)
+	}
+	*m = FieldMapping(full)
+	return nil
+}
+
+// JSONAPIProvider fetches events from a JSON API according to a JSONAPIDefinition.
+type JSONAPIProvider struct {
+	// Definition describes the source.
+	Definition JSONAPIDefinition
+	// BaseURL overrides Definition.URL, e.g. for testing.
+	BaseURL string
+	// Client is the HTTP client used for requests.
+	Client *http.Client
+
+	location *time.Location
+	duration time.Duration
+}
+
+// NewJSONAPIProvider validates a definition and creates a provider for it.
+func NewJSONAPIProvider(def JSONAPIDefinition) (*JSONAPIProvider, error) {
+	if def.SourceName == "" {
+		return nil, fmt.Errorf("json api definition: source_name is required")
+	}
+	if def.URL == "" {
+		return nil, fmt.Errorf("json api %s: url is required", def.SourceName)
+	}
+	for _, name := range []string{"title", "date_start"} {
+		if _, ok := def.Fields[name]; !ok {
+			return nil, fmt.Errorf("json api %s: a %s field is required", def.SourceName, name)
+		}
+	}
+
+	p := &JSONAPIProvider{
+		Definition: def,
+		BaseURL:    def.URL,
+		Client:     SharedClient,
+		location:   time.UTC,
+		duration:   2 * time.Hour,
+	}
+
+	if def.TimeZone != "" {
+		loc, err := time.LoadLocation(def.TimeZone)
+		if err != nil {
+			return nil, fmt.Errorf("json api %s: time_zone: %w", def.SourceName, err)
+		}
+		p.location = loc
+	}
+
+	if def.DefaultDuration != "" {
+		d, err := time.ParseDuration(def.DefaultDuration)
+		if err != nil {
+			return nil, fmt.Errorf("json api %s: default_duration: %w", def.SourceName, err)
+		}
+		p.duration = d
+	}
+
+	if pg := def.Pagination; pg != nil {
+		switch pg.Type {
+		case PaginatePage, PaginateOffset, PaginateLink:
+		case PaginateCursor:
+			if pg.CursorPath == "" {
+				return nil, fmt.Errorf("json api %s: cursor pagination needs a cursor_path", def.SourceName)
+			}
+		default:
+			return nil, fmt.Errorf("json api %s: unknown pagination type %q", def.SourceName, pg.Type)
+		}
+	}
+
+	return p, nil
+}
+
+// SourceName returns the unique identifier for this provider.
+func (p *JSONAPIProvider) SourceName() string {
+	return p.Definition.SourceName
+}
+
+// FetchEvents retrieves the configured endpoint, and its further pages if
+// the definition paginates, and returns the items found at ItemsPath.
+func (p *JSONAPIProvider) FetchEvents(ctx context.Context) ([]RawEvent, error) {
+	u, err := url.Parse(p.BaseURL)
+	if err != nil {
+		return nil, fmt.Errorf("parsing url: %w", err)
+	}
+
+	today := time.Now().Format("2006-01-02")
+	q := u.Query()
+	for k, v := range p.Definition.Query {
+		q.Set(k, strings.ReplaceAll(v, "{today}", today))
+	}
+	u.RawQuery = q.Encode()
+
+	header := http.Header{}
+	header.Set("Accept", "application/json")
+	for k, v := range p.Definition.Headers {
+		header.Set(k, v)
+	}
+
+	paginator := Paginator{Name: p.SourceName(), Header: header}
+	if pg := p.Definition.Pagination; pg != nil {
+		paginator.Strategy = pg.Type
+		paginator.Param = pg.Param
+		paginator.SizeParam = pg.SizeParam
+		paginator.PageSize = pg.PageSize
+		paginator.Start = pg.Start
+		paginator.MaxPages = pg.MaxPages
+		paginator.MaxItems = pg.MaxItems
+	}
+	return paginator.Fetch(ctx, p.Client, u.String(), p.parsePage)
+}
+
+// parsePage reads the items of a response and, for cursor pagination, the
+// cursor of the next page.
+func (p *JSONAPIProvider) parsePage(resp *http.Response, _ string) ([]RawEvent, string, error) {
+	var body any
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, "", fmt.Errorf("decoding response: %w", err)
+	}
+
+	items, ok := lookupPath(body, p.Definition.ItemsPath).([]any)
+	if !ok {
+		return nil, "", fmt.Errorf("no array at items path %q", p.Definition.ItemsPath)
+	}
+
+	events := make([]RawEvent, 0, len(items))
+	for _, item := range items {
+		if obj, ok := item.(map[string]any); ok {
+			events = append(events, RawEvent(obj))
+		}
+	}
+
+	var next string
+	if pg := p.Definition.Pagination; pg != nil && pg.CursorPath != "" {
+		next = valueToString(lookupPath(body, pg.CursorPath))
+	}
+	return events, next, nil
+}
+
+// MapEvent converts a RawEvent into the internal Event structure using the
+// configured field mappings. Items without a title or start date are dropped.
+func (p *JSONAPIProvider) MapEvent(raw RawEvent) *Event {
+	title := html.UnescapeString(p.text(raw, "title"))
+	if title == "" {
+		return nil
+	}
+
+	dateStart, ok := p.date(raw, "date_start")
+	if !ok {
+		log.Printf("JSON API %s: skipping %q, missing or invalid start date", p.SourceName(), title)
+		return nil
+	}
+	dateEnd, ok := p.date(raw, "date_end")
+	if !ok || dateEnd.Before(dateStart) {
+		dateEnd = dateStart.Add(p.duration)
+	}
+
+	link := p.text(raw, "url")
+
+	id := p.text(raw, "id")
+	if id == "" {
+		id = link
+	}
+	if id == "" {
+		hash := sha256.Sum256([]byte(title + dateStart.String()))
+		id = hex.EncodeToString(hash[:8])
+	}
+	id = p.Definition.IDPrefix + id
+
+	location := p.text(raw, "location")
+	if location == "" {
+		location = p.Definition.Location
+	}
+	category := p.text(raw, "category")
+	if category == "" {
+		category = p.Definition.Category
+	}
+	if category == "" {
+		category = "general"
+	}
+
+	lat, _ := strconv.ParseFloat(p.text(raw, "latitude"), 64)
+	long, _ := strconv.ParseFloat(p.text(raw, "longitude"), 64)
+
+	return &Event{
+		ID:          id,
+		Title:       title,
+		Description: stripHTML(p.text(raw, "description")),
+		DateStart:   dateStart,
+		DateEnd:     dateEnd,
+		Location:    location,
+		URL:         link,
+		ImageURL:    p.text(raw, "image"),
+		SourceName:  p.SourceName(),
+		SourceID:    id,
+		Topics:      p.list(raw, "topics"),
+		Category:    category,
+		Latitude:    lat,
+		Longitude:   long,
+	}
+}
+
+// text resolves a field mapping to a string.
+func (p *JSONAPIProvider) text(raw RawEvent, field string) string {
+	m, ok := p.Definition.Fields[field]
+	if !ok {
+		return ""
+	}
+
+	if m.Template != "" {
+		if v := expandTemplate(m.Template, raw); v != "" {
+			return v
+		}
+		return m.Default
+	}
+
+	var values []string
+	for _, path := range m.Paths {
+		v := valueToString(lookupPath(map[string]any(raw), path))
+		if v == "" {
+			continue
+		}
+		if m.Join == "" {
+			return v
+		}
+		values = append(values, v)
+	}
+	if len(values) > 0 {
+		return strings.Join(values, m.Join)
+	}
+	return m.Default
+}
+
+// list resolves a field mapping to a list of strings, accepting arrays or
+// comma separated text.
+func (p *JSONAPIProvider) list(raw RawEvent, field string) []string {
+	result := []string{}
+	m, ok := p.Definition.Fields[field]
+	if !ok {
+		return result
+	}
+	for _, path := range m.Paths {
+		switch v := lookupPath(map[string]any(raw), path).(type) {
+		case []any:
+			for _, item := range v {
+				if s := valueToString(item); s != "" {
+					result = append(result, s)
+				}
+			}
+		case string:
+			for _, s := range strings.Split(v, ",") {
+				if s = strings.TrimSpace(s); s != "" {
+					result = append(result, s)
+				}
+			}
+		}
+		if len(result) > 0 {
+			break
+		}
+	}
+	return result
+}
+
+// date resolves a field mapping to a time. Numbers are read as Unix seconds
+// (or milliseconds when large enough).
+func (p *JSONAPIProvider) date(raw RawEvent, field string) (time.Time, bool) {
+	m, ok := p.Definition.Fields[field]
+	if !ok {
+		return time.Time{}, false
+	}
+	for _, path := range m.Paths {
+		switch v := lookupPath(map[string]any(raw), path).(type) {
+		case float64:
+			if v > 1e12 {
+				return time.UnixMilli(int64(v)).In(p.location), true
+			}
+			return time.Unix(int64(v), 0).In(p.location), true
+		case string:
+			for _, layout := range p.Definition.DateLayouts {
+				if t, err := time.ParseInLocation(layout, v, p.location); err == nil {
+					return t, true
+				}
+			}
+			if t, ok := parseFlexibleTime(v, p.location); ok {
+				return t, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+// lookupPath evaluates a path such as "Detail.{en,it,de}.Title" or
+// "ImageGallery[0].ImageUrl" against decoded JSON. Brace alternatives are
+// tried in order and the first non-empty result is returned.
+func lookupPath(data any, path string) any {
+	if path == "" {
+		return data
+	}
+
+	segment, rest, _ := strings.Cut(path, ".")
+
+	// Expand alternatives, e.g. "{en,it,de}".
+	if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+		for _, alt := range strings.Split(segment[1:len(segment)-1], ",") {
+			next := strings.TrimSpace(alt)
+			if rest != "" {
+				next += "." + rest
+			}
+			if v := lookupPath(data, next); !isEmptyValue(v) {
+				return v
+			}
+		}
+		return nil
+	}
+
+	// Split off array indexes, e.g. "ImageGallery[0]".
+	key := segment
+	var indexes []int
+	if i := strings.Index(segment, "["); i >= 0 {
+		key = segment[:i]
+		for _, part := range strings.Split(segment[i+1:], "[") {
+			n, err := strconv.Atoi(strings.TrimSuffix(part, "]"))
+			if err != nil {
+				return nil
+			}
+			indexes = append(indexes, n)
+		}
+	}
+
+	current := data
+	if key != "" {
+		obj, ok := current.(map[string]any)
+		if !ok {
+			return nil
+		}
+		current = obj[key]
+	}
+	for _, n := range indexes {
+		arr, ok := current.([]any)
+		if !ok || n < 0 || n >= len(arr) {
+			return nil
+		}
+		current = arr[n]
+	}
+
+	return lookupPath(current, rest)
+}
+
+// expandTemplate replaces "{{path}}" placeholders with values from raw.
+// It returns "" if any placeholder is empty.
+func expandTemplate(tmpl string, raw RawEvent) string {
+	var b strings.Builder
+	rest := tmpl
+	for {
+		start := strings.Index(rest, "{{")
+		if start < 0 {
+			b.WriteString(rest)
+			return b.String()
+		}
+		end := strings.Index(rest[start:], "}}")
+		if end < 0 {
+			b.WriteString(rest)
+			return b.String()
+		}
+		b.WriteString(rest[:start])
+		path := strings.TrimSpace(rest[start+2 : start+end])
+		v := valueToString(lookupPath(map[string]any(raw), path))
+		if v == "" {
+			return ""
+		}
+		b.WriteString(v)
+		rest = rest[start+end+2:]
+	}
+}
+
+// valueToString formats scalar JSON values; objects and arrays yield "".
+func valueToString(v any) string {
+	switch t := v.(type) {
+	case string:
+		return strings.TrimSpace(t)
+	case float64:
+		return strconv.FormatFloat(t, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(t)
+	}
+	return ""
+}
+
+// isEmptyValue reports whether a decoded JSON value carries no data.
+func isEmptyValue(v any) bool {
+	switch t := v.(type) {
+	case nil:
+		return true
+	case string:
+		return strings.TrimSpace(t) == ""
+	case []any:
+		return len(t) == 0
+	case map[string]any:
+		return len(t) == 0
+	}
+	return false
+}
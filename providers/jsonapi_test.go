@@ -0,0 +1,138 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONAPIProvider_FetchEvents(t *testing.T) {
+	fixture, err := os.ReadFile("fixtures/noi.json")
+	require.NoError(t, err)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/Event", r.URL.Path)
+		assert.Equal(t, "Bolzano", r.URL.Query().Get("locationfilter"))
+		assert.Equal(t, time.Now().Format("2006-01-02"), r.URL.Query().Get("datefrom"))
+		assert.Equal(t, "secret", r.Header.Get("X-Api-Key"))
+		_, _ = w.Write(fixture)
+	}))
+	defer server.Close()
+
+	var def JSONAPIDefinition
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"source_name": "odh_custom",
+		"url": "`+server.URL+`/v1/Event",
+		"query": {"locationfilter": "Bolzano", "datefrom": "{today}"},
+		"headers": {"X-Api-Key": "secret"},
+		"items_path": "Items",
+		"fields": {
+			"id": "Id",
+			"title": "Detail.{de,it,en}.Title",
+			"description": ["Detail.{en,it,de}.BaseText", "Detail.{en,it,de}.IntroText"],
+			"date_start": "DateBegin",
+			"date_end": "DateEnd",
+			"image": "ImageGallery[0].ImageUrl",
+			"url": {"template": "https://opendatahub.com/events/{{Id}}"},
+			"location": {"paths": ["ContactInfos.en.City"], "default": "Bolzano"}
+		},
+		"id_prefix": "odh-",
+		"time_zone": "Europe/Rome",
+		"category": "Tech"
+	}`), &def))
+
+	p, err := NewJSONAPIProvider(def)
+	require.NoError(t, err)
+
+	events, err := p.FetchEvents(context.Background())
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+
+	mapped := p.MapEvent(events[0])
+	require.NotNil(t, mapped)
+	assert.Equal(t, "odh-noi-123", mapped.SourceID)
+	// No German title: the Italian one is the first available fallback.
+	assert.Equal(t, "NOI Techpark Summit IT", mapped.Title)
+	assert.Equal(t, "Innovation starts here.", mapped.Description)
+	assert.Equal(t, "https://noi.bz.it/image.jpg", mapped.ImageURL)
+	assert.Equal(t, "https://opendatahub.com/events/noi-123", mapped.URL)
+	assert.Equal(t, "Bolzano", mapped.Location)
+	assert.Equal(t, "Tech", mapped.Category)
+	assert.Equal(t, "odh_custom", mapped.SourceName)
+	assert.Equal(t, time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC), mapped.DateStart.UTC())
+	assert.Equal(t, 8*time.Hour, mapped.DateEnd.Sub(mapped.DateStart))
+}
+
+func TestJSONAPIProvider_MapEvent_Joins(t *testing.T) {
+	p, err := NewJSONAPIProvider(JSONAPIDefinition{
+		SourceName: "hackathons",
+		URL:        "https://example.com/api",
+		Fields: map[string]FieldMapping{
+			"id":         {Paths: []string{"id"}},
+			"title":      {Paths: []string{"name"}},
+			"date_start": {Paths: []string{"starts_at"}},
+			"location":   {Paths: []string{"city", "country_code"}, Join: ", "},
+			"topics":     {Paths: []string{"topics"}},
+			"latitude":   {Paths: []string{"geo.lat"}},
+		},
+	})
+	require.NoError(t, err)
+
+	event := p.MapEvent(RawEvent{
+		"id":           float64(42),
+		"name":         "EuroHack",
+		"starts_at":    float64(1900000000),
+		"city":         "Berlin",
+		"country_code": "DE",
+		"topics":       []any{"ai", "cloud"},
+		"geo":          map[string]any{"lat": 52.52},
+	})
+	require.NotNil(t, event)
+	assert.Equal(t, "42", event.SourceID)
+	assert.Equal(t, "Berlin, DE", event.Location)
+	assert.Equal(t, []string{"ai", "cloud"}, event.Topics)
+	assert.InDelta(t, 52.52, event.Latitude, 0.001)
+	assert.Equal(t, int64(1900000000), event.DateStart.Unix())
+	assert.Equal(t, "general", event.Category)
+
+	assert.Nil(t, p.MapEvent(RawEvent{"name": "No date"}))
+}
+
+func TestJSONAPIProvider_FetchEvents_CursorPagination(t *testing.T) {
+	pages := map[string]string{
+		"":   `{"data": [{"title": "A", "start": "2030-01-01T10:00:00Z"}], "meta": {"next": "c2"}}`,
+		"c2": `{"data": [{"title": "B", "start": "2030-01-02T10:00:00Z"}], "meta": {"next": null}}`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(pages[r.URL.Query().Get("cursor")]))
+	}))
+	defer server.Close()
+
+	var def JSONAPIDefinition
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"source_name": "paged",
+		"url": "`+server.URL+`",
+		"items_path": "data",
+		"fields": {"title": "title", "date_start": "start"},
+		"pagination": {"type": "cursor", "param": "cursor", "cursor_path": "meta.next"}
+	}`), &def))
+
+	p, err := NewJSONAPIProvider(def)
+	require.NoError(t, err)
+
+	events, err := p.FetchEvents(context.Background())
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, "B", events[1]["title"])
+
+	def.Pagination = &JSONAPIPagination{Type: "cursor"}
+	_, err = NewJSONAPIProvider(def)
+	assert.Error(t, err, "cursor pagination needs a cursor path")
+}
@@ -0,0 +1,170 @@
+package providers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+// SyncLockCollection is the PocketBase collection holding the sync lock.
+// Keeping the lock in the database makes it visible to every process sharing
+// the data directory and lets it outlive a crash, after which it expires.
+const SyncLockCollection = "sync_locks"
+
+// syncLockName is the name of the lock record guarding event writes.
+const syncLockName = "sync"
+
+// SyncLockTTL is how long a lock stays valid without a heartbeat. The holder
+// refreshes it every SyncLockTTL/3, so only locks of crashed processes expire.
+var SyncLockTTL = 10 * time.Minute
+
+// ErrSyncLocked is returned when another process holds the sync lock.
+var ErrSyncLocked = errors.New("another sync is running")
+
+// SyncLockedError reports the lock that prevented a sync from starting.
+type SyncLockedError struct {
+	Lock SyncLockInfo
+}
+
+// Error implements the error interface.
+func (e *SyncLockedError) Error() string {
+	return fmt.Sprintf("%v (held by %s, job %s)", ErrSyncLocked, e.Lock.Holder, e.Lock.JobID)
+}
+
+// Unwrap makes errors.Is(err, ErrSyncLocked) work.
+func (e *SyncLockedError) Unwrap() error {
+	return ErrSyncLocked
+}
+
+// SyncLockInfo describes the state of the sync lock.
+type SyncLockInfo struct {
+	Locked      bool       `json:"locked"`
+	Holder      string     `json:"holder,omitempty"`
+	JobID       string     `json:"job_id,omitempty"`
+	AcquiredAt  *time.Time `json:"acquired_at,omitempty"`
+	HeartbeatAt *time.Time `json:"heartbeat_at,omitempty"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+}
+
+// lockHolder identifies this process in the lock record.
+var lockHolder = func() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+	return fmt.Sprintf("%s:%d", host, os.Getpid())
+}()
+
+// SyncLockStatus returns the current state of the sync lock. Expired locks
+// are reported as unlocked.
+func SyncLockStatus(app core.App) (SyncLockInfo, error) {
+	record, err := findSyncLock(app)
+	if err != nil {
+		return SyncLockInfo{}, err
+	}
+	if record == nil || lockExpired(record) {
+		return SyncLockInfo{}, nil
+	}
+	return lockInfo(record), nil
+}
+
+// acquireSyncLock takes the sync lock for jobID, replacing an expired lock.
+// If the lock collection does not exist, locking is skipped.
+func acquireSyncLock(app core.App, jobID string) error {
+	return app.RunInTransaction(func(txApp core.App) error {
+		collection, err := txApp.FindCollectionByNameOrId(SyncLockCollection)
+		if err != nil {
+			return nil
+		}
+
+		record, err := findSyncLock(txApp)
+		if err != nil {
+			return err
+		}
+		if record != nil {
+			if !lockExpired(record) {
+				return &SyncLockedError{Lock: lockInfo(record)}
+			}
+			log.Printf("Removing stale sync lock of %s (job %s)", record.GetString("holder"), record.GetString("job_id"))
+			if err := txApp.Delete(record); err != nil {
+				return fmt.Errorf("removing stale sync lock: %w", err)
+			}
+		}
+
+		now := types.NowDateTime()
+		record = core.NewRecord(collection)
+		record.Set("name", syncLockName)
+		record.Set("holder", lockHolder)
+		record.Set("job_id", jobID)
+		record.Set("acquired_at", now)
+		record.Set("heartbeat_at", now)
+		if err := txApp.Save(record); err != nil {
+			return fmt.Errorf("saving sync lock: %w", err)
+		}
+		return nil
+	})
+}
+
+// refreshSyncLock updates the heartbeat of the lock held by jobID.
+func refreshSyncLock(app core.App, jobID string) {
+	record, err := findSyncLock(app)
+	if err != nil || record == nil || record.GetString("job_id") != jobID {
+		return
+	}
+	record.Set("heartbeat_at", types.NowDateTime())
+	if err := app.Save(record); err != nil {
+		log.Printf("Failed to refresh sync lock: %v", err)
+	}
+}
+
+// releaseSyncLock removes the lock if it is still held by jobID.
+func releaseSyncLock(app core.App, jobID string) {
+	record, err := findSyncLock(app)
+	if err != nil || record == nil || record.GetString("job_id") != jobID {
+		return
+	}
+	if err := app.Delete(record); err != nil {
+		log.Printf("Failed to release sync lock: %v", err)
+	}
+}
+
+// findSyncLock returns the lock record, or nil if there is none.
+func findSyncLock(app core.App) (*core.Record, error) {
+	collection, err := app.FindCollectionByNameOrId(SyncLockCollection)
+	if err != nil {
+		return nil, nil
+	}
+	records, err := app.FindRecordsByFilter(collection, "name = {:name}", "", 1, 0, map[string]any{"name": syncLockName})
+	if err != nil {
+		return nil, fmt.Errorf("finding sync lock: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+	return records[0], nil
+}
+
+// lockExpired reports whether the holder stopped sending heartbeats.
+func lockExpired(record *core.Record) bool {
+	return time.Since(record.GetDateTime("heartbeat_at").Time()) > SyncLockTTL
+}
+
+// lockInfo converts a lock record.
+func lockInfo(record *core.Record) SyncLockInfo {
+	acquiredAt := record.GetDateTime("acquired_at").Time()
+	heartbeatAt := record.GetDateTime("heartbeat_at").Time()
+	expiresAt := heartbeatAt.Add(SyncLockTTL)
+	return SyncLockInfo{
+		Locked:      true,
+		Holder:      record.GetString("holder"),
+		JobID:       record.GetString("job_id"),
+		AcquiredAt:  &acquiredAt,
+		HeartbeatAt: &heartbeatAt,
+		ExpiresAt:   &expiresAt,
+	}
+}
@@ -16,13 +16,18 @@ func TestMuseionProvider_FetchEvents(t *testing.T) {
 	require.NoError(t, err)
 
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" {
+			http.NotFound(w, r)
+			return
+		}
 		assert.Equal(t, "/en/events", r.URL.Path)
 		w.WriteHeader(http.StatusOK)
 		_, _ = w.Write(fixture)
 	}))
 	defer server.Close()
 
-	p := NewMuseionProvider()
+	p, err := NewMuseionProvider()
+	require.NoError(t, err)
 	p.BaseURL = server.URL + "/en/events"
 
 	events, err := p.FetchEvents(context.Background())
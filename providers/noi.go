@@ -2,9 +2,9 @@ package providers
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
 	"strings"
 	"time"
 )
@@ -20,7 +20,7 @@ type NOIProvider struct {
 func NewNOIProvider() *NOIProvider {
 	return &NOIProvider{
 		BaseURL: "https://tourism.api.opendatahub.com/v1/Event",
-		Client:  &http.Client{Timeout: 30 * time.Second},
+		Client:  SharedClient,
 	}
 }
 
@@ -29,115 +29,113 @@ func (p *NOIProvider) SourceName() string {
 	return "noi"
 }
 
-// FetchEvents retrieves raw event data from the Open Data Hub API for NOI Techpark.
+// FetchEvents retrieves the upcoming Bolzano events from the Open Data Hub
+// API and keeps those at the NOI Techpark.
 func (p *NOIProvider) FetchEvents(ctx context.Context) ([]RawEvent, error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.BaseURL, nil)
+	return collectEvents(ctx, p)
+}
+
+// noiPageSize is the number of items requested per page; validated safe
+// for the ODH API.
+const noiPageSize = 200
+
+// StreamEvents retrieves the upcoming NOI Techpark events page by page.
+func (p *NOIProvider) StreamEvents(ctx context.Context, yield func([]RawEvent) error) error {
+	u, err := url.Parse(p.BaseURL)
 	if err != nil {
-		return nil, fmt.Errorf("creating request: %w", err)
+		return fmt.Errorf("parsing url: %w", err)
 	}
-
-	q := req.URL.Query()
-	q.Set("pagesize", "200") // Validated safe page size for ODH
+	q := u.Query()
 	q.Set("locationfilter", "Bolzano")
-
-	var allEvents []RawEvent
-	page := 1
-
-	for {
-		q.Set("pagenumber", fmt.Sprintf("%d", page))
-		req.URL.RawQuery = q.Encode()
-
-		// Clone the request for each iteration to avoid reusing body/context issues if any (though here we reuse client)
-		// But wait, we can reuse the request object if we are careful, or just create a new one inside the loop.
-		// Actually, creating a new request inside the loop is safer but higher overhead.
-		// Given the `req` is created outside, we just update URL.
-		// However, `req` body is nil for GET, so it's fine.
-
-		resp, err := p.Client.Do(req)
-		if err != nil {
-			return nil, fmt.Errorf("fetching events page %d: %w", page, err)
-		}
-
-		if resp.StatusCode != http.StatusOK {
-			_ = resp.Body.Close()
-			return nil, fmt.Errorf("unexpected status page %d: %d", page, resp.StatusCode)
+	// NOI events are picked on the client, so leave past ones out server-side
+	q.Set("datefrom", time.Now().Format("2006-01-02"))
+	if since := syncCursor(ctx); since != "" {
+		q.Set("updatefrom", since)
+	}
+	u.RawQuery = q.Encode()
+
+	paginator := Paginator{
+		Name:      p.SourceName(),
+		Strategy:  PaginatePage,
+		Param:     "pagenumber",
+		SizeParam: "pagesize",
+		PageSize:  noiPageSize,
+		MaxPages:  odhMaxItems / noiPageSize,
+		MaxItems:  odhMaxItems,
+	}
+	return paginator.Stream(ctx, p.Client, u.String(), decodeODHPage, func(items []RawEvent) error {
+		var events []RawEvent
+		for _, item := range items {
+			if isNOIEvent(item) {
+				events = append(events, item)
+			}
 		}
-
-		var localResult ODHResponse
-		if err := json.NewDecoder(resp.Body).Decode(&localResult); err != nil {
-			_ = resp.Body.Close()
-			return nil, fmt.Errorf("decoding response page %d: %w", page, err)
+		if len(events) == 0 {
+			return nil
 		}
-		_ = resp.Body.Close()
+		return yield(events)
+	})
+}
 
-		if len(localResult.Items) == 0 {
-			break
-		}
+// NextCursor returns the date of startedAt for the ODH updatefrom parameter.
+func (p *NOIProvider) NextCursor(startedAt time.Time) string {
+	return odhCursor(startedAt)
+}
 
-		hasNOI := func(s string) bool {
-			s = strings.ToUpper(s)
-			return strings.Contains(s, "NOI") || strings.Contains(s, "VOLTA") || strings.Contains(s, "TECHPARK")
-		}
+// isNOIEvent reports whether the title, district or address of an ODH event
+// mentions the NOI Techpark.
+func isNOIEvent(item RawEvent) bool {
+	hasNOI := func(s string) bool {
+		s = strings.ToUpper(s)
+		return strings.Contains(s, "NOI") || strings.Contains(s, "VOLTA") || strings.Contains(s, "TECHPARK")
+	}
 
-		for _, item := range localResult.Items {
-			match := false
+	match := false
 
-			// Check Title (multilingual)
-			if details, ok := item["Detail"].(map[string]any); ok {
-				for _, lang := range []string{"en", "it", "de"} {
-					if langData, ok := details[lang].(map[string]any); ok {
-						if title, ok := langData["Title"].(string); ok && hasNOI(title) {
-							match = true
-							break
-						}
-					}
+	// Check Title (multilingual)
+	if details, ok := item["Detail"].(map[string]any); ok {
+		for _, lang := range []string{"en", "it", "de"} {
+			if langData, ok := details[lang].(map[string]any); ok {
+				if title, ok := langData["Title"].(string); ok && hasNOI(title) {
+					match = true
+					break
 				}
 			}
+		}
+	}
 
-			// Check Location
-			if !match {
-				if locInfo, ok := item["LocationInfo"].(map[string]any); ok {
-					// Check District/Municipality names if available
-					if district, ok := locInfo["DistrictInfo"].(map[string]any); ok {
-						if nameMap, ok := district["Name"].(map[string]any); ok {
-							for _, lang := range []string{"en", "it", "de"} {
-								if name, ok := nameMap[lang].(string); ok && hasNOI(name) {
-									match = true
-									break
-								}
-							}
+	// Check Location
+	if !match {
+		if locInfo, ok := item["LocationInfo"].(map[string]any); ok {
+			// Check District/Municipality names if available
+			if district, ok := locInfo["DistrictInfo"].(map[string]any); ok {
+				if nameMap, ok := district["Name"].(map[string]any); ok {
+					for _, lang := range []string{"en", "it", "de"} {
+						if name, ok := nameMap[lang].(string); ok && hasNOI(name) {
+							match = true
+							break
 						}
 					}
 				}
 			}
+		}
+	}
 
-			// Also check if any ContactInfo address contains Volta/NOI
-			if !match {
-				if contacts, ok := item["ContactInfos"].(map[string]any); ok {
-					for _, lang := range []string{"en", "it", "de"} {
-						if contact, ok := contacts[lang].(map[string]any); ok {
-							if addr, ok := contact["Address"].(string); ok && hasNOI(addr) {
-								match = true
-								break
-							}
-						}
+	// Also check if any ContactInfo address contains Volta/NOI
+	if !match {
+		if contacts, ok := item["ContactInfos"].(map[string]any); ok {
+			for _, lang := range []string{"en", "it", "de"} {
+				if contact, ok := contacts[lang].(map[string]any); ok {
+					if addr, ok := contact["Address"].(string); ok && hasNOI(addr) {
+						match = true
+						break
 					}
 				}
 			}
-
-			if match {
-				allEvents = append(allEvents, RawEvent(item))
-			}
-		}
-
-		// If we got fewer items than page size, we are done
-		if len(localResult.Items) < 200 {
-			break
 		}
-		page++
 	}
 
-	return allEvents, nil
+	return match
 }
 
 // MapEvent reuses logic from helpers but sets source to "noi"
@@ -6,6 +6,7 @@ import (
 	"net/http/httptest"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -19,6 +20,7 @@ func TestNOIProvider_FetchEvents(t *testing.T) {
 	// Create mock server
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		assert.Contains(t, r.URL.Path, "/v1/Event")
+		assert.Equal(t, time.Now().Format("2006-01-02"), r.URL.Query().Get("datefrom"))
 		w.WriteHeader(http.StatusOK)
 		_, _ = w.Write(fixture)
 	}))
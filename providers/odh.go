@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
 	"time"
 )
 
@@ -22,7 +23,7 @@ type ODHProvider struct {
 func NewODHProvider() *ODHProvider {
 	return &ODHProvider{
 		BaseURL: "https://tourism.opendatahub.com/v1/Event",
-		Client:  &http.Client{Timeout: 30 * time.Second},
+		Client:  SharedClient,
 	}
 }
 
@@ -37,41 +38,69 @@ type ODHResponse struct {
 	Items        []map[string]any `json:"Items"`
 }
 
-// FetchEvents retrieves events from the Open Data Hub API.
+// odhPageSize is the number of items requested per page from the ODH API.
+const odhPageSize = 100
+
+// odhMaxItems caps the upcoming events read from the ODH API. It is well
+// above the size of the dataset, so that the cap only stops runaway paging.
+const odhMaxItems = 20000
+
+// FetchEvents retrieves all upcoming events from the Open Data Hub API.
 func (p *ODHProvider) FetchEvents(ctx context.Context) ([]RawEvent, error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.BaseURL, nil)
+	return collectEvents(ctx, p)
+}
+
+// StreamEvents retrieves all upcoming events page by page.
+func (p *ODHProvider) StreamEvents(ctx context.Context, yield func([]RawEvent) error) error {
+	u, err := url.Parse(p.BaseURL)
 	if err != nil {
-		return nil, fmt.Errorf("creating request: %w", err)
+		return fmt.Errorf("parsing url: %w", err)
 	}
-
-	q := req.URL.Query()
-	q.Set("pagenumber", "1")
-	q.Set("pagesize", "50") // Increased page size
-	q.Set("active", "true")
+	q := u.Query()
+	// Inactive events are fetched too: they are cancelled, see MapEvent
 	q.Set("odalactive", "true")
 	q.Set("datefrom", time.Now().Format("2006-01-02"))
-	req.URL.RawQuery = q.Encode()
-
-	resp, err := p.Client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("fetching events: %w", err)
+	if since := syncCursor(ctx); since != "" {
+		q.Set("updatefrom", since)
 	}
-	defer func() { _ = resp.Body.Close() }()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status: %d", resp.StatusCode)
+	u.RawQuery = q.Encode()
+
+	paginator := Paginator{
+		Name:      p.SourceName(),
+		Strategy:  PaginatePage,
+		Param:     "pagenumber",
+		SizeParam: "pagesize",
+		PageSize:  odhPageSize,
+		MaxPages:  odhMaxItems / odhPageSize,
+		MaxItems:  odhMaxItems,
 	}
+	return paginator.Stream(ctx, p.Client, u.String(), decodeODHPage, yield)
+}
+
+// NextCursor returns the date of startedAt for the updatefrom parameter, which
+// selects the events changed since that day.
+func (p *ODHProvider) NextCursor(startedAt time.Time) string {
+	return odhCursor(startedAt)
+}
 
+// odhCursor formats startedAt for the ODH updatefrom parameter. UTC dates are
+// never later than the local date of the API, so no change is missed.
+func odhCursor(startedAt time.Time) string {
+	return startedAt.UTC().Format("2006-01-02")
+}
+
+// decodeODHPage reads the items of an ODH API page.
+func decodeODHPage(resp *http.Response, _ string) ([]RawEvent, string, error) {
 	var result ODHResponse
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, fmt.Errorf("decoding response: %w", err)
+		return nil, "", fmt.Errorf("decoding response: %w", err)
 	}
 
 	events := make([]RawEvent, len(result.Items))
 	for i, item := range result.Items {
 		events[i] = RawEvent(item)
 	}
-	return events, nil
+	return events, "", nil
 }
 
 // MapEvent transforms raw ODH event data into a unified Event structure.
@@ -97,6 +126,11 @@ func (p *ODHProvider) MapEvent(raw RawEvent) *Event {
 		return nil
 	}
 
+	// ODH keeps cancelled events, flagged inactive
+	if active, ok := raw["Active"].(bool); ok && !active {
+		event.Status = EventCancelled
+	}
+
 	return event
 }
 
@@ -0,0 +1,255 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Pagination strategies.
+const (
+	// PaginateNone fetches a single page.
+	PaginateNone = ""
+	// PaginatePage increments a page number query parameter.
+	PaginatePage = "page"
+	// PaginateOffset advances an offset query parameter by the items received.
+	PaginateOffset = "offset"
+	// PaginateCursor follows a cursor read from each page: either a token set
+	// as query parameter, or the URL of the next page.
+	PaginateCursor = "cursor"
+	// PaginateLink follows the Link: <...>; rel="next" response header.
+	PaginateLink = "link"
+	// PaginateHTML follows a "next page" link found in an HTML page.
+	PaginateHTML = "html"
+)
+
+// Pagination safety caps, used when a Paginator sets none.
+var (
+	DefaultMaxPages = 20
+	DefaultMaxItems = 5000
+)
+
+// Paginator walks the pages of a listing. Page and offset pagination stop at
+// the first empty or short page; cursor, link and HTML pagination stop when
+// a page has no next page. All stop at MaxPages and MaxItems, which marks
+// the fetch partial when more items are available, unless CapIsComplete.
+type Paginator struct {
+	// Name identifies the source in logs.
+	Name string
+	// Strategy is one of the Paginate constants.
+	Strategy string
+	// Param is the query parameter holding the page number, the offset or
+	// the cursor. It defaults to "page" and "offset"; a cursor without Param
+	// is the URL of the next page.
+	Param string
+	// SizeParam is the query parameter holding PageSize, if any.
+	SizeParam string
+	// PageSize is the number of items requested per page. A page with fewer
+	// items is the last one.
+	PageSize int
+	// Start is the first page number (default 1) or offset (default 0).
+	Start int
+	// MaxPages caps the pages fetched (default DefaultMaxPages).
+	MaxPages int
+	// MaxItems caps the items returned (default DefaultMaxItems).
+	MaxItems int
+	// CapIsComplete means that stopping at MaxPages or MaxItems still
+	// returns every item that matters, e.g. because older pages only hold
+	// past events, so the fetch is not marked partial.
+	CapIsComplete bool
+	// Header holds extra request headers.
+	Header http.Header
+	// Scraper checks robots.txt before every page, see doScraperRequest.
+	Scraper bool
+}
+
+// PageParser reads the items of a page. For cursor and HTML pagination it
+// also returns the next cursor or link, or "" on the last page.
+type PageParser func(resp *http.Response, pageURL string) (items []RawEvent, next string, err error)
+
+// Fetch walks the pages starting at firstURL. On error it returns the items
+// of the previous pages along with the error, so that callers may keep them.
+func (p Paginator) Fetch(ctx context.Context, client *http.Client, firstURL string, parse PageParser) ([]RawEvent, error) {
+	var items []RawEvent
+	err := p.Stream(ctx, client, firstURL, parse, func(page []RawEvent) error {
+		items = append(items, page...)
+		return nil
+	})
+	return items, err
+}
+
+// Stream walks the pages starting at firstURL and passes the items of each
+// page to yield. It stops and returns the error if yield fails.
+func (p Paginator) Stream(ctx context.Context, client *http.Client, firstURL string, parse PageParser, yield func([]RawEvent) error) error {
+	maxPages, maxItems := p.MaxPages, p.MaxItems
+	if maxPages <= 0 {
+		maxPages = DefaultMaxPages
+	}
+	if maxItems <= 0 {
+		maxItems = DefaultMaxItems
+	}
+
+	u, err := url.Parse(firstURL)
+	if err != nil {
+		return fmt.Errorf("parsing url: %w", err)
+	}
+	q := u.Query()
+	if p.SizeParam != "" && p.PageSize > 0 {
+		q.Set(p.SizeParam, strconv.Itoa(p.PageSize))
+	}
+
+	param, position := p.Param, p.Start
+	switch p.Strategy {
+	case PaginatePage:
+		param = withDefault(param, "page")
+		if position == 0 {
+			position = 1
+		}
+		q.Set(param, strconv.Itoa(position))
+	case PaginateOffset:
+		param = withDefault(param, "offset")
+		q.Set(param, strconv.Itoa(position))
+	}
+	u.RawQuery = q.Encode()
+
+	total := 0
+	visited := make(map[string]bool)
+	pageURL := u.String()
+
+	for page := 1; pageURL != "" && !visited[pageURL]; page++ {
+		if page > maxPages {
+			if !p.CapIsComplete {
+				log.Printf("Pagination of %s: stopped after %d pages, more events available", p.Name, maxPages)
+				markPartial(ctx)
+			}
+			break
+		}
+		visited[pageURL] = true
+
+		pageItems, next, err := p.fetchPage(ctx, client, pageURL, parse)
+		if err != nil {
+			return fmt.Errorf("page %d: %w", page, err)
+		}
+		count := len(pageItems)
+		capped := total+count >= maxItems
+		if capped {
+			if (total+count > maxItems || next != "") && !p.CapIsComplete {
+				log.Printf("Pagination of %s: stopped at %d items", p.Name, maxItems)
+				markPartial(ctx)
+			}
+			pageItems = pageItems[:maxItems-total]
+		}
+		total += len(pageItems)
+		if len(pageItems) > 0 {
+			if err := yield(pageItems); err != nil {
+				return err
+			}
+		}
+		if capped {
+			break
+		}
+
+		current := pageURL
+		pageURL = ""
+		switch p.Strategy {
+		case PaginatePage, PaginateOffset:
+			if count == 0 || (p.PageSize > 0 && count < p.PageSize) {
+				break
+			}
+			if p.Strategy == PaginatePage {
+				position++
+			} else {
+				position += count
+			}
+			q.Set(param, strconv.Itoa(position))
+			u.RawQuery = q.Encode()
+			pageURL = u.String()
+		case PaginateCursor:
+			if next == "" {
+				break
+			}
+			if param == "" {
+				pageURL = resolveURL(current, next)
+				break
+			}
+			q.Set(param, next)
+			u.RawQuery = q.Encode()
+			pageURL = u.String()
+		case PaginateLink, PaginateHTML:
+			if next != "" {
+				pageURL = resolveURL(current, next)
+			}
+		}
+	}
+	return nil
+}
+
+// fetchPage requests and parses a single page and returns the link or
+// cursor of the next page.
+func (p Paginator) fetchPage(ctx context.Context, client *http.Client, pageURL string, parse PageParser) ([]RawEvent, string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, pageURL, nil)
+	if err != nil {
+		return nil, "", fmt.Errorf("creating request: %w", err)
+	}
+	for k, values := range p.Header {
+		for _, v := range values {
+			req.Header.Add(k, v)
+		}
+	}
+
+	var resp *http.Response
+	if p.Scraper {
+		resp, err = doScraperRequest(client, req)
+	} else {
+		resp, err = doRequest(client, req)
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("fetching events: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("unexpected status: %d", resp.StatusCode)
+	}
+
+	items, next, err := parse(resp, pageURL)
+	if err != nil {
+		return nil, "", err
+	}
+	if p.Strategy == PaginateLink {
+		next = linkNext(resp.Header.Values("Link"))
+	}
+	return items, next, nil
+}
+
+// linkNext returns the target of the rel="next" link in Link headers.
+func linkNext(headers []string) string {
+	for _, header := range headers {
+		for _, link := range strings.Split(header, ",") {
+			target, params, ok := strings.Cut(link, ";")
+			if !ok {
+				continue
+			}
+			target = strings.TrimSpace(target)
+			if !strings.HasPrefix(target, "<") || !strings.HasSuffix(target, ">") {
+				continue
+			}
+			for _, param := range strings.Split(params, ";") {
+				key, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+				if !strings.EqualFold(key, "rel") {
+					continue
+				}
+				for _, rel := range strings.Fields(strings.Trim(value, `"`)) {
+					if strings.EqualFold(rel, "next") {
+						return target[1 : len(target)-1]
+					}
+				}
+			}
+		}
+	}
+	return ""
+}
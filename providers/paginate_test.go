@@ -0,0 +1,196 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// pagedItems serves 7 items; pages are selected by the query parameters of
+// the strategy under test.
+func pagedItems(t *testing.T, handler func(w http.ResponseWriter, r *http.Request, items []int)) *httptest.Server {
+	items := []int{1, 2, 3, 4, 5, 6, 7}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handler(w, r, items)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// writeItems writes items as {"items": [{"id": 1}, ...], "next": next}.
+func writeItems(w http.ResponseWriter, items []int, next string) {
+	objects := make([]map[string]any, len(items))
+	for i, id := range items {
+		objects[i] = map[string]any{"id": id}
+	}
+	_ = json.NewEncoder(w).Encode(map[string]any{"items": objects, "next": next})
+}
+
+// parseItems reads the items and next cursor written by writeItems.
+func parseItems(resp *http.Response, _ string) ([]RawEvent, string, error) {
+	var body struct {
+		Items []map[string]any `json:"items"`
+		Next  string           `json:"next"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, "", err
+	}
+	events := make([]RawEvent, len(body.Items))
+	for i, item := range body.Items {
+		events[i] = RawEvent(item)
+	}
+	return events, body.Next, nil
+}
+
+// ids returns the ids of the fetched items.
+func ids(events []RawEvent) []int {
+	result := make([]int, len(events))
+	for i, e := range events {
+		result[i] = int(e["id"].(float64))
+	}
+	return result
+}
+
+// window returns up to size items starting at offset.
+func window(items []int, offset, size int) []int {
+	if offset >= len(items) {
+		return nil
+	}
+	return items[offset:min(offset+size, len(items))]
+}
+
+func TestPaginator_Page(t *testing.T) {
+	server := pagedItems(t, func(w http.ResponseWriter, r *http.Request, items []int) {
+		page, _ := strconv.Atoi(r.URL.Query().Get("p"))
+		size, _ := strconv.Atoi(r.URL.Query().Get("size"))
+		assert.Equal(t, "x", r.URL.Query().Get("keep"))
+		writeItems(w, window(items, (page-1)*size, size), "")
+	})
+
+	p := Paginator{Strategy: PaginatePage, Param: "p", SizeParam: "size", PageSize: 3}
+	events, err := p.Fetch(context.Background(), server.Client(), server.URL+"?keep=x", parseItems)
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 3, 4, 5, 6, 7}, ids(events))
+}
+
+func TestPaginator_Offset(t *testing.T) {
+	requests := 0
+	server := pagedItems(t, func(w http.ResponseWriter, r *http.Request, items []int) {
+		requests++
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		writeItems(w, window(items, offset, 4), "")
+	})
+
+	// Without a page size, pagination stops at the first empty page
+	p := Paginator{Strategy: PaginateOffset}
+	events, err := p.Fetch(context.Background(), server.Client(), server.URL, parseItems)
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 3, 4, 5, 6, 7}, ids(events))
+	assert.Equal(t, 3, requests)
+}
+
+func TestPaginator_Cursor(t *testing.T) {
+	server := pagedItems(t, func(w http.ResponseWriter, r *http.Request, items []int) {
+		offset, _ := strconv.Atoi(r.URL.Query().Get("after"))
+		next := ""
+		if offset+3 < len(items) {
+			next = strconv.Itoa(offset + 3)
+		}
+		writeItems(w, window(items, offset, 3), next)
+	})
+
+	p := Paginator{Strategy: PaginateCursor, Param: "after"}
+	events, err := p.Fetch(context.Background(), server.Client(), server.URL, parseItems)
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 3, 4, 5, 6, 7}, ids(events))
+}
+
+func TestPaginator_Link(t *testing.T) {
+	server := pagedItems(t, func(w http.ResponseWriter, r *http.Request, items []int) {
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		if page == 0 {
+			page = 1
+		}
+		if page*3 < len(items) {
+			w.Header().Add("Link", `<https://example.com/prev>; rel="prev"`)
+			w.Header().Add("Link", fmt.Sprintf(`</items?page=%d>; rel="next"`, page+1))
+		}
+		writeItems(w, window(items, (page-1)*3, 3), "")
+	})
+
+	p := Paginator{Strategy: PaginateLink}
+	events, err := p.Fetch(context.Background(), server.Client(), server.URL+"/items", parseItems)
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 3, 4, 5, 6, 7}, ids(events))
+}
+
+func TestPaginator_Caps(t *testing.T) {
+	requests := 0
+	server := pagedItems(t, func(w http.ResponseWriter, r *http.Request, items []int) {
+		requests++
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		writeItems(w, window(items, (page-1)*2, 2), "")
+	})
+
+	// A capped fetch is marked partial
+	ctx, partial := withPartial(context.Background())
+	p := Paginator{Strategy: PaginatePage, PageSize: 2, MaxPages: 2}
+	events, err := p.Fetch(ctx, server.Client(), server.URL, parseItems)
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 3, 4}, ids(events))
+	assert.Equal(t, 2, requests)
+	assert.True(t, partial.Load())
+
+	ctx, partial = withPartial(context.Background())
+	p = Paginator{Strategy: PaginatePage, PageSize: 2, MaxItems: 3}
+	events, err = p.Fetch(ctx, server.Client(), server.URL, parseItems)
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 3}, ids(events))
+	assert.True(t, partial.Load())
+
+	ctx, partial = withPartial(context.Background())
+	p = Paginator{Strategy: PaginatePage, PageSize: 2, MaxPages: 4}
+	events, err = p.Fetch(ctx, server.Client(), server.URL, parseItems)
+	require.NoError(t, err)
+	assert.Len(t, events, 7)
+	assert.False(t, partial.Load(), "the last page is within the cap")
+
+	// A planned cap is not
+	ctx, partial = withPartial(context.Background())
+	p = Paginator{Strategy: PaginatePage, PageSize: 2, MaxPages: 2, CapIsComplete: true}
+	events, err = p.Fetch(ctx, server.Client(), server.URL, parseItems)
+	require.NoError(t, err)
+	assert.Len(t, events, 4)
+	assert.False(t, partial.Load())
+}
+
+func TestPaginator_ErrorKeepsPreviousPages(t *testing.T) {
+	server := pagedItems(t, func(w http.ResponseWriter, r *http.Request, items []int) {
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		if page == 2 {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		writeItems(w, window(items, (page-1)*3, 3), "")
+	})
+
+	p := Paginator{Strategy: PaginatePage, PageSize: 3}
+	events, err := p.Fetch(context.Background(), server.Client(), server.URL, parseItems)
+	assert.Error(t, err)
+	assert.Equal(t, []int{1, 2, 3}, ids(events))
+}
+
+func TestLinkNext(t *testing.T) {
+	assert.Equal(t, "https://api.example.com/items?page=2",
+		linkNext([]string{`<https://api.example.com/items?page=1>; rel="first", <https://api.example.com/items?page=2>; rel="next"`}))
+	assert.Equal(t, "/p/3", linkNext([]string{`</p/3>; rel="next last"`}))
+	assert.Equal(t, "", linkNext([]string{`</p/1>; rel="prev"`}))
+	assert.Equal(t, "", linkNext(nil))
+}
@@ -25,9 +25,15 @@ type Event struct {
 	SourceID    string    `json:"source_id"`
 	Topics      []string  `json:"topics"`
 	Category    string    `json:"category"`
-	IsNew       bool      `json:"is_new"`
-	Latitude    float64   `json:"latitude"`
-	Longitude   float64   `json:"longitude"`
+	// FirstSeenAt and LastSeenAt are when the sync first and last found the
+	// event at its source. Mappers leave them zero.
+	FirstSeenAt time.Time `json:"first_seen_at"`
+	LastSeenAt  time.Time `json:"last_seen_at"`
+	// Status is one of the lifecycle statuses such as EventCancelled. Mappers
+	// set it from source signals; if empty, the sync derives it.
+	Status    string  `json:"status"`
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
 }
 
 // EventProvider defines the interface that all event sources must implement.
@@ -42,3 +48,45 @@ type EventProvider interface {
 	// MapEvent transforms raw event data into a unified Event structure.
 	MapEvent(raw RawEvent) *Event
 }
+
+// MultiEventMapper is an optional interface for providers whose raw items can
+// describe several events, such as weekly roundup blog posts. When a provider
+// implements it, the sync uses MapEvents instead of MapEvent.
+type MultiEventMapper interface {
+	// MapEvents transforms one raw item into zero or more events.
+	MapEvents(raw RawEvent) []*Event
+}
+
+// StreamingProvider is an optional interface for large sources. When a
+// provider implements it, the sync maps and upserts each batch as soon as it
+// arrives instead of collecting all events first, so memory stays bounded
+// and the batches written before a failure are kept.
+type StreamingProvider interface {
+	// StreamEvents fetches raw events and passes them to yield in batches,
+	// typically one per page. It stops and returns the error if yield fails.
+	StreamEvents(ctx context.Context, yield func([]RawEvent) error) error
+}
+
+// IncrementalProvider is an optional interface for sources that can return
+// only the items changed since the previous sync. The sync passes the cursor
+// stored after the last successful sync to FetchEvents or StreamEvents
+// through the context, see syncCursor. Without a cursor, or when a full sync
+// is due, the provider fetches everything.
+type IncrementalProvider interface {
+	// NextCursor returns the cursor to store after a successful sync that
+	// started at startedAt, e.g. the start time in the source's format.
+	NextCursor(startedAt time.Time) string
+}
+
+// collectEvents gathers all batches of a StreamingProvider, for its FetchEvents.
+func collectEvents(ctx context.Context, p StreamingProvider) ([]RawEvent, error) {
+	var events []RawEvent
+	err := p.StreamEvents(ctx, func(batch []RawEvent) error {
+		events = append(events, batch...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return events, nil
+}
@@ -0,0 +1,140 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync/atomic"
+	"time"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+// RemovalGrace is how long an upcoming event may be missing from the
+// complete fetches of its source before it is marked removed, so that a
+// source briefly dropping an item does not hide it.
+var RemovalGrace = 24 * time.Hour
+
+// ReconcileMinRatio is the share of a provider's upcoming events a fetch must
+// contain to be reconciled. A fetch missing more of them more likely comes
+// from a broken source than from mass cancellations.
+var ReconcileMinRatio = 0.5
+
+// partialKey is the context key of the partial flag of a provider fetch.
+type partialKey struct{}
+
+// withPartial returns a context whose fetch markPartial can flag.
+func withPartial(ctx context.Context) (context.Context, *atomic.Bool) {
+	partial := new(atomic.Bool)
+	return context.WithValue(ctx, partialKey{}, partial), partial
+}
+
+// markPartial flags the fetch under ctx as missing part of its source, e.g.
+// because one of several feeds failed or pagination was capped. Such a fetch
+// does not tell which events its source dropped, so it is not reconciled.
+func markPartial(ctx context.Context) {
+	if partial, ok := ctx.Value(partialKey{}).(*atomic.Bool); ok {
+		partial.Store(true)
+	}
+}
+
+// reconcileEvents marks the upcoming events of a provider that a complete
+// fetch at seenAt did not contain as removed, once they have been missing
+// for RemovalGrace. seen holds the source ids of the fetched events. It
+// returns the number of events removed.
+func reconcileEvents(app core.App, provider string, seen map[string]bool, seenAt time.Time) (int, error) {
+	collection, err := app.FindCollectionByNameOrId("events")
+	if err != nil {
+		return 0, fmt.Errorf("finding events collection: %w", err)
+	}
+
+	writeMu.Lock()
+	defer writeMu.Unlock()
+
+	records, err := app.FindRecordsByFilter(
+		collection,
+		"source_name = {:provider} && status != {:removed} && date_end >= {:now}",
+		"",
+		0,
+		0,
+		map[string]any{"provider": provider, "removed": EventRemoved, "now": seenAt},
+	)
+	if err != nil {
+		return 0, fmt.Errorf("finding events of %s: %w", provider, err)
+	}
+
+	var missing []*core.Record
+	for _, record := range records {
+		if !seen[record.GetString("source_id")] {
+			missing = append(missing, record)
+		}
+	}
+	if present := len(records) - len(missing); len(records) > 0 && float64(present) < ReconcileMinRatio*float64(len(records)) {
+		return 0, fmt.Errorf("fetch contained only %d of %d upcoming events, not reconciling", present, len(records))
+	}
+
+	removed := 0
+	for _, record := range missing {
+		lastSeen := record.GetDateTime("last_seen_at")
+		if lastSeen.IsZero() {
+			// Not known how long it has been missing, which the backfill
+			// migration should have prevented
+			continue
+		}
+		if seenAt.Sub(lastSeen.Time()) < RemovalGrace {
+			continue
+		}
+		record.Set("status", EventRemoved)
+		changes := diffEvent(record)
+		if err := app.Save(record); err != nil {
+			return removed, fmt.Errorf("removing %s: %w", record.GetString("source_id"), err)
+		}
+		saveRevision(app, record, provider, changes)
+		removed++
+	}
+	if removed > 0 {
+		log.Printf("Marked %d events of %s as removed", removed, provider)
+	}
+	return removed, nil
+}
+
+// touchSeenEvents refreshes last_seen_at of the listed events of a provider,
+// or of all its events if sourceIDs is nil, after a fetch that returned them
+// exactly as the previous one did.
+func touchSeenEvents(app core.App, provider string, sourceIDs []string, seenAt time.Time) error {
+	collection, err := app.FindCollectionByNameOrId("events")
+	if err != nil {
+		return fmt.Errorf("finding events collection: %w", err)
+	}
+
+	writeMu.Lock()
+	defer writeMu.Unlock()
+	return touchEvents(app, collection, provider, sourceIDs, seenAt)
+}
+
+// touchEvents sets last_seen_at of the listed events of a provider to
+// seenAt, or of all its events if sourceIDs is nil. It updates the rows
+// directly: a sighting is bookkeeping, not a change of the event.
+func touchEvents(app core.App, collection *core.Collection, provider string, sourceIDs []string, seenAt time.Time) error {
+	seen, err := types.ParseDateTime(seenAt)
+	if err != nil {
+		return err
+	}
+	query := "UPDATE {{" + collection.Name + "}} SET [[last_seen_at]] = {:seen}" +
+		" WHERE [[source_name]] = {:provider} AND [[status]] != {:removed} AND [[last_seen_at]] < {:seen}"
+	params := map[string]any{"seen": seen, "provider": provider, "removed": EventRemoved}
+	if sourceIDs != nil {
+		ids, err := json.Marshal(sourceIDs)
+		if err != nil {
+			return err
+		}
+		query += " AND [[source_id]] IN (SELECT value FROM json_each({:ids}))"
+		params["ids"] = string(ids)
+	}
+	if _, err := app.DB().NewQuery(query).Bind(params).Execute(); err != nil {
+		return fmt.Errorf("updating last_seen_at of %s: %w", provider, err)
+	}
+	return nil
+}
@@ -0,0 +1,347 @@
+package providers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/cron"
+)
+
+// ProvidersCollection is the PocketBase collection holding the runtime
+// configuration of providers.
+const ProvidersCollection = "providers"
+
+// Provider types with a fixed built-in implementation. Definition based
+// types (DefinitionTypeHTML, DefinitionTypeJSONAPI, DefinitionTypeTribeEvents)
+// are accepted as well.
+const (
+	ProviderTypeODH            = "odh"
+	ProviderTypeEuroHackathons = "euro_hackathons"
+	ProviderTypeDrinbz         = "drinbz"
+	ProviderTypeNOI            = "noi"
+	ProviderTypeUnibz          = "unibz"
+	ProviderTypeMuseion        = "museion"
+	ProviderTypeICS            = "ics"
+	ProviderTypeFeed           = "feed"
+	ProviderTypeSchemaOrg      = "schemaorg"
+)
+
+// ProviderConfig is the runtime configuration of a provider, as stored in a
+// record of the providers collection.
+type ProviderConfig struct {
+	// Type selects the implementation, e.g. "odh" or "json_api".
+	Type string `json:"type"`
+	// SourceName is the unique identifier of the source.
+	SourceName string `json:"source_name"`
+	// Enabled controls whether the provider takes part in syncs.
+	Enabled bool `json:"enabled"`
+	// BaseURL overrides the default endpoint of the provider, if set.
+	BaseURL string `json:"base_url"`
+	// Options holds type specific settings as a JSON object. For definition
+	// based types it is the definition itself.
+	Options json.RawMessage `json:"options"`
+	// Schedule is a cron expression for the provider's sync job.
+	Schedule string `json:"schedule"`
+	// Timeout bounds a single sync of the provider (default DefaultProviderTimeout).
+	Timeout time.Duration `json:"timeout"`
+}
+
+// providerOptions are the options shared by the feed based types.
+type providerOptions struct {
+	Category string `json:"category"`
+	Location string `json:"location"`
+	TimeZone string `json:"time_zone"`
+	// Feeds lists additional feeds or pages of the same source.
+	Feeds []feedOptions `json:"feeds"`
+}
+
+// feedOptions configures a single feed or page of a source.
+type feedOptions struct {
+	URL      string `json:"url"`
+	Category string `json:"category"`
+	Location string `json:"location"`
+}
+
+// NewProviderFromConfig instantiates the provider described by cfg.
+func NewProviderFromConfig(cfg ProviderConfig) (EventProvider, error) {
+	if cfg.SourceName == "" {
+		cfg.SourceName = cfg.Type
+	}
+
+	switch cfg.Type {
+	case ProviderTypeODH, ProviderTypeEuroHackathons, ProviderTypeDrinbz, ProviderTypeNOI:
+		// These sources have a fixed name, as their events are already
+		// stored under it.
+		if cfg.SourceName != cfg.Type {
+			return nil, fmt.Errorf("provider %s: source_name must be %q", cfg.SourceName, cfg.Type)
+		}
+	}
+
+	switch cfg.Type {
+	case ProviderTypeODH:
+		p := NewODHProvider()
+		p.BaseURL = withDefault(cfg.BaseURL, p.BaseURL)
+		return p, nil
+	case ProviderTypeEuroHackathons:
+		p := NewEuroHackathonsProvider()
+		p.BaseURL = withDefault(cfg.BaseURL, p.BaseURL)
+		return p, nil
+	case ProviderTypeDrinbz:
+		p := NewDrinbzProvider()
+		p.BaseURL = withDefault(cfg.BaseURL, p.BaseURL)
+		return p, nil
+	case ProviderTypeNOI:
+		p := NewNOIProvider()
+		p.BaseURL = withDefault(cfg.BaseURL, p.BaseURL)
+		return p, nil
+
+	case ProviderTypeUnibz, ProviderTypeMuseion:
+		newScraper := NewUnibzProvider
+		if cfg.Type == ProviderTypeMuseion {
+			newScraper = NewMuseionProvider
+		}
+		p, err := newScraper()
+		if err != nil {
+			return nil, fmt.Errorf("provider %s: %w", cfg.SourceName, err)
+		}
+		p.Definition.SourceName = cfg.SourceName
+		p.BaseURL = withDefault(cfg.BaseURL, p.BaseURL)
+		return p, nil
+
+	case ProviderTypeICS, ProviderTypeFeed, ProviderTypeSchemaOrg:
+		var opts providerOptions
+		if err := decodeOptions(cfg.Options, &opts); err != nil {
+			return nil, fmt.Errorf("provider %s: %w", cfg.SourceName, err)
+		}
+		loc := time.UTC
+		if opts.TimeZone != "" {
+			l, err := time.LoadLocation(opts.TimeZone)
+			if err != nil {
+				return nil, fmt.Errorf("provider %s: time_zone: %w", cfg.SourceName, err)
+			}
+			loc = l
+		}
+		if cfg.BaseURL != "" {
+			opts.Feeds = append(opts.Feeds, feedOptions{URL: cfg.BaseURL})
+		}
+		if len(opts.Feeds) == 0 {
+			return nil, fmt.Errorf("provider %s: base_url or options.feeds is required", cfg.SourceName)
+		}
+
+		switch cfg.Type {
+		case ProviderTypeICS:
+			p := NewICSProvider()
+			p.Name = cfg.SourceName
+			p.TimeZone = loc
+			for _, f := range opts.Feeds {
+				p.Feeds = append(p.Feeds, ICSFeed{
+					URL:        f.URL,
+					SourceName: cfg.SourceName,
+					Category:   withDefault(f.Category, opts.Category),
+					Location:   withDefault(f.Location, opts.Location),
+				})
+			}
+			return p, nil
+		case ProviderTypeFeed:
+			p := NewFeedProvider()
+			p.Name = cfg.SourceName
+			p.TimeZone = loc
+			for _, f := range opts.Feeds {
+				p.Feeds = append(p.Feeds, FeedSource{
+					URL:        f.URL,
+					SourceName: cfg.SourceName,
+					Category:   withDefault(f.Category, opts.Category),
+					Location:   withDefault(f.Location, opts.Location),
+				})
+			}
+			return p, nil
+		default:
+			p := NewSchemaOrgProvider()
+			p.Name = cfg.SourceName
+			p.TimeZone = loc
+			for _, f := range opts.Feeds {
+				p.Pages = append(p.Pages, SchemaOrgPage{
+					URL:        f.URL,
+					SourceName: cfg.SourceName,
+					Category:   withDefault(f.Category, opts.Category),
+				})
+			}
+			return p, nil
+		}
+
+	case DefinitionTypeHTML, DefinitionTypeJSONAPI, DefinitionTypeTribeEvents:
+		// The options are the definition; the record's columns take precedence.
+		def := map[string]any{}
+		if err := decodeOptions(cfg.Options, &def); err != nil {
+			return nil, fmt.Errorf("provider %s: %w", cfg.SourceName, err)
+		}
+		def["type"] = cfg.Type
+		def["source_name"] = cfg.SourceName
+		if cfg.BaseURL != "" {
+			def["url"] = cfg.BaseURL
+		}
+		data, err := json.Marshal(def)
+		if err != nil {
+			return nil, fmt.Errorf("provider %s: encoding definition: %w", cfg.SourceName, err)
+		}
+		return ParseSourceDefinition(data)
+
+	default:
+		return nil, fmt.Errorf("provider %s: unknown type %q", cfg.SourceName, cfg.Type)
+	}
+}
+
+// LoadProviderConfigs reads all records of the providers collection. It
+// returns no configs, and no error, if the collection does not exist.
+func LoadProviderConfigs(app core.App) ([]ProviderConfig, error) {
+	collection, err := app.FindCollectionByNameOrId(ProvidersCollection)
+	if err != nil {
+		return nil, nil
+	}
+
+	records, err := app.FindAllRecords(collection)
+	if err != nil {
+		return nil, fmt.Errorf("loading provider configs: %w", err)
+	}
+
+	configs := make([]ProviderConfig, 0, len(records))
+	for _, record := range records {
+		cfg := ProviderConfig{
+			Type:       record.GetString("type"),
+			SourceName: record.GetString("source_name"),
+			Enabled:    record.GetBool("enabled"),
+			BaseURL:    record.GetString("base_url"),
+			Schedule:   record.GetString("schedule"),
+			Timeout:    time.Duration(record.GetInt("timeout")) * time.Second,
+		}
+		if err := record.UnmarshalJSONField("options", &cfg.Options); err != nil {
+			log.Printf("Provider %s: ignoring invalid options: %v", cfg.SourceName, err)
+		}
+		configs = append(configs, cfg)
+	}
+	return configs, nil
+}
+
+// ActiveProviders returns the providers that take part in a sync. It starts
+// from the compiled-in Providers and applies the providers collection on
+// top: a disabled record removes the source with the same name, an enabled
+// record replaces it (or adds a new source). Invalid records are logged and
+// skipped, so that one broken configuration does not stop the other sources.
+func ActiveProviders(app core.App) ([]EventProvider, error) {
+	active, err := loadActiveProviders(app)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]EventProvider, len(active))
+	for i, ap := range active {
+		result[i] = ap.EventProvider
+	}
+	return result, nil
+}
+
+// activeProvider is a provider taking part in syncs, with its sync settings.
+type activeProvider struct {
+	EventProvider
+	// Schedule is the cron expression of its sync job.
+	Schedule string
+	// Timeout bounds a single sync of the provider.
+	Timeout time.Duration
+}
+
+// loadActiveProviders is ActiveProviders including the sync settings.
+func loadActiveProviders(app core.App) ([]activeProvider, error) {
+	configs, err := LoadProviderConfigs(app)
+	if err != nil {
+		return nil, err
+	}
+	return applyProviderConfigs(Providers, configs), nil
+}
+
+// applyProviderConfigs overlays configs on the defaults, keeping their order.
+// Missing or invalid schedules and timeouts are replaced by the defaults.
+func applyProviderConfigs(defaults []EventProvider, configs []ProviderConfig) []activeProvider {
+	byName := make(map[string]ProviderConfig, len(configs))
+	overrides := make(map[string]EventProvider, len(configs))
+	var added []EventProvider
+
+	for _, cfg := range configs {
+		name := withDefault(cfg.SourceName, cfg.Type)
+		byName[name] = cfg
+		if !cfg.Enabled {
+			continue
+		}
+		p, err := NewProviderFromConfig(cfg)
+		if err != nil {
+			log.Printf("Skipping provider %s: %v", name, err)
+			continue
+		}
+		overrides[name] = p
+		added = append(added, p)
+	}
+
+	var candidates []EventProvider
+	seen := make(map[string]bool)
+	for _, p := range defaults {
+		seen[p.SourceName()] = true
+		if cfg, ok := byName[p.SourceName()]; ok && !cfg.Enabled {
+			continue
+		}
+		if override, ok := overrides[p.SourceName()]; ok {
+			p = override
+		}
+		candidates = append(candidates, p)
+	}
+	for _, p := range added {
+		if !seen[p.SourceName()] {
+			seen[p.SourceName()] = true
+			candidates = append(candidates, p)
+		}
+	}
+
+	result := make([]activeProvider, len(candidates))
+	for i, p := range candidates {
+		cfg := byName[p.SourceName()]
+
+		schedule := strings.TrimSpace(cfg.Schedule)
+		if schedule != "" {
+			if _, err := cron.NewSchedule(schedule); err != nil {
+				log.Printf("Provider %s: invalid schedule %q, using default: %v", p.SourceName(), schedule, err)
+				schedule = ""
+			}
+		}
+		timeout := cfg.Timeout
+		if timeout <= 0 {
+			timeout = DefaultProviderTimeout
+		}
+
+		result[i] = activeProvider{
+			EventProvider: p,
+			Schedule:      withDefault(schedule, DefaultSyncSchedule),
+			Timeout:       timeout,
+		}
+	}
+	return result
+}
+
+// decodeOptions decodes a JSON options object into v. Empty options are allowed.
+func decodeOptions(data json.RawMessage, v any) error {
+	if s := strings.TrimSpace(string(data)); s == "" || s == "null" {
+		return nil
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("decoding options: %w", err)
+	}
+	return nil
+}
+
+// withDefault returns s, or def if s is empty.
+func withDefault(s, def string) string {
+	if s == "" {
+		return def
+	}
+	return s
+}
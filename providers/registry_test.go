@@ -0,0 +1,99 @@
+package providers
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewProviderFromConfig(t *testing.T) {
+	p, err := NewProviderFromConfig(ProviderConfig{Type: "odh", BaseURL: "https://odh.example.com/v1/Event"})
+	require.NoError(t, err)
+	require.IsType(t, &ODHProvider{}, p)
+	assert.Equal(t, "https://odh.example.com/v1/Event", p.(*ODHProvider).BaseURL)
+
+	p, err = NewProviderFromConfig(ProviderConfig{Type: "unibz", SourceName: "unibz"})
+	require.NoError(t, err)
+	require.IsType(t, &SelectorScraperProvider{}, p)
+	unibz, err := NewUnibzProvider()
+	require.NoError(t, err)
+	assert.Equal(t, unibz.BaseURL, p.(*SelectorScraperProvider).BaseURL)
+
+	p, err = NewProviderFromConfig(ProviderConfig{
+		Type:       "ics",
+		SourceName: "city_calendar",
+		BaseURL:    "https://city.example.com/events.ics",
+		Options:    json.RawMessage(`{"category": "Civic", "location": "Bolzano", "time_zone": "Europe/Rome"}`),
+	})
+	require.NoError(t, err)
+	require.IsType(t, &ICSProvider{}, p)
+	ics := p.(*ICSProvider)
+	assert.Equal(t, "city_calendar", ics.SourceName())
+	assert.Equal(t, "Europe/Rome", ics.TimeZone.String())
+	require.Len(t, ics.Feeds, 1)
+	assert.Equal(t, ICSFeed{
+		URL: "https://city.example.com/events.ics", SourceName: "city_calendar",
+		Category: "Civic", Location: "Bolzano",
+	}, ics.Feeds[0])
+
+	p, err = NewProviderFromConfig(ProviderConfig{
+		Type:       "json_api",
+		SourceName: "odh_museums",
+		BaseURL:    "https://api.example.com/events",
+		Options:    json.RawMessage(`{"url": "https://old.example.com", "fields": {"title": "name", "date_start": "start"}}`),
+	})
+	require.NoError(t, err)
+	require.IsType(t, &JSONAPIProvider{}, p)
+	assert.Equal(t, "odh_museums", p.SourceName())
+	assert.Equal(t, "https://api.example.com/events", p.(*JSONAPIProvider).BaseURL)
+}
+
+func TestNewProviderFromConfig_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  ProviderConfig
+	}{
+		{"Unknown type", ProviderConfig{Type: "ftp", SourceName: "x"}},
+		{"Renamed builtin", ProviderConfig{Type: "odh", SourceName: "odh2"}},
+		{"Feed without URL", ProviderConfig{Type: "feed", SourceName: "x"}},
+		{"Bad options", ProviderConfig{Type: "feed", SourceName: "x", Options: json.RawMessage(`[1]`)}},
+		{"Bad definition", ProviderConfig{Type: "html", SourceName: "x", BaseURL: "https://x"}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := NewProviderFromConfig(tc.cfg)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestApplyProviderConfigs(t *testing.T) {
+	defaults := []EventProvider{NewODHProvider(), NewDrinbzProvider(), NewNOIProvider()}
+
+	active := applyProviderConfigs(defaults, []ProviderConfig{
+		{Type: "drinbz", SourceName: "drinbz", Enabled: false},
+		{Type: "noi", SourceName: "noi", Enabled: true, BaseURL: "https://noi.example.com", Schedule: "*/5 * * * *", Timeout: 30 * time.Second},
+		{Type: "tribe_events", SourceName: "venue", Enabled: true, BaseURL: "https://venue.example.com"},
+		{Type: "ftp", SourceName: "broken", Enabled: true},
+		{Type: "odh", SourceName: "odh", Enabled: true, Schedule: "every now and then"},
+	})
+
+	names := make([]string, len(active))
+	for i, p := range active {
+		names[i] = p.SourceName()
+	}
+	assert.Equal(t, []string{"odh", "noi", "venue"}, names)
+	assert.Equal(t, "https://noi.example.com", active[1].EventProvider.(*NOIProvider).BaseURL)
+	assert.Equal(t, "*/5 * * * *", active[1].Schedule)
+	assert.Equal(t, 30*time.Second, active[1].Timeout)
+
+	// Invalid or missing settings fall back to the defaults
+	assert.Equal(t, DefaultSyncSchedule, active[0].Schedule)
+	assert.Equal(t, DefaultProviderTimeout, active[0].Timeout)
+
+	assert.Len(t, applyProviderConfigs(defaults, nil), 3)
+}
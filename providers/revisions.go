@@ -0,0 +1,99 @@
+package providers
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/pocketbase/pocketbase/core"
+)
+
+// EventRevisionsCollection is the PocketBase collection storing the changes
+// the sync made to events.
+const EventRevisionsCollection = "event_revisions"
+
+// revisionFields are the event fields whose changes are recorded.
+var revisionFields = []string{
+	"title", "description", "date_start", "date_end", "location", "url",
+	"image_url", "topics", "category", "status",
+}
+
+// FieldChange is the previous and new value of a field.
+type FieldChange struct {
+	Old any `json:"old"`
+	New any `json:"new"`
+}
+
+// EventRevision is a change the sync made to an event.
+type EventRevision struct {
+	ID        string                 `json:"id"`
+	Event     string                 `json:"event"`
+	Provider  string                 `json:"provider"`
+	ChangedAt time.Time              `json:"changed_at"`
+	Changes   map[string]FieldChange `json:"changes"`
+}
+
+// diffEvent returns the changes of an event record since it was loaded, or
+// nil if none of revisionFields changed.
+func diffEvent(record *core.Record) map[string]FieldChange {
+	original := record.Original()
+	var changes map[string]FieldChange
+	for _, field := range revisionFields {
+		if original.GetString(field) == record.GetString(field) {
+			continue
+		}
+		if changes == nil {
+			changes = make(map[string]FieldChange)
+		}
+		changes[field] = FieldChange{Old: original.Get(field), New: record.Get(field)}
+	}
+	return changes
+}
+
+// saveRevision records the changes a provider made to an event. Failures are
+// logged: the event itself is already saved.
+func saveRevision(app core.App, record *core.Record, provider string, changes map[string]FieldChange) {
+	if len(changes) == 0 {
+		return
+	}
+	collection, err := app.FindCollectionByNameOrId(EventRevisionsCollection)
+	if err != nil {
+		return
+	}
+
+	revision := core.NewRecord(collection)
+	revision.Set("event", record.Id)
+	revision.Set("provider", provider)
+	revision.Set("changed_at", time.Now())
+	revision.Set("changes", changes)
+	if err := app.Save(revision); err != nil {
+		log.Printf("Failed to record revision of event %s: %v", record.Id, err)
+	}
+}
+
+// EventHistory returns the revisions of an event, newest first.
+func EventHistory(app core.App, eventID string) ([]EventRevision, error) {
+	collection, err := app.FindCollectionByNameOrId(EventRevisionsCollection)
+	if err != nil {
+		return nil, fmt.Errorf("finding event revisions collection: %w", err)
+	}
+	records, err := app.FindRecordsByFilter(collection, "event = {:event}", "-changed_at", 0, 0, map[string]any{"event": eventID})
+	if err != nil {
+		return nil, fmt.Errorf("finding revisions: %w", err)
+	}
+
+	history := make([]EventRevision, 0, len(records))
+	for _, record := range records {
+		revision := EventRevision{
+			ID:        record.Id,
+			Event:     record.GetString("event"),
+			Provider:  record.GetString("provider"),
+			ChangedAt: record.GetDateTime("changed_at").Time(),
+		}
+		if err := record.UnmarshalJSONField("changes", &revision.Changes); err != nil {
+			log.Printf("Event revision %s: invalid changes: %v", record.Id, err)
+		}
+		history = append(history, revision)
+	}
+	return history, nil
+}
@@ -0,0 +1,225 @@
+package providers
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrDisallowedByRobots is returned for pages a site's robots.txt excludes.
+var ErrDisallowedByRobots = errors.New("disallowed by robots.txt")
+
+// RobotsTTL is how long a fetched robots.txt is trusted.
+var RobotsTTL = 24 * time.Hour
+
+// maxRobotsSize caps the robots.txt read, as recommended by RFC 9309.
+const maxRobotsSize = 500 << 10
+
+// robotsRule is an Allow or Disallow line.
+type robotsRule struct {
+	allow   bool
+	length  int
+	pattern *regexp.Regexp
+}
+
+// robotsRules are the rules of the group applying to UserAgent.
+type robotsRules struct {
+	rules      []robotsRule
+	crawlDelay time.Duration
+	fetchedAt  time.Time
+}
+
+// robotsCache holds the rules per scheme and host.
+var robotsCache = struct {
+	sync.Mutex
+	byHost map[string]*robotsRules
+}{byHost: make(map[string]*robotsRules)}
+
+// doScraperRequest sends a request of an HTML scraper. Unlike APIs, scraped
+// sites are checked against their robots.txt first.
+func doScraperRequest(client *http.Client, req *http.Request) (*http.Response, error) {
+	rules, err := loadRobots(client, req)
+	if err != nil {
+		return nil, err
+	}
+	if !rules.allowed(req.URL) {
+		return nil, fmt.Errorf("%s: %w", req.URL, ErrDisallowedByRobots)
+	}
+	return doRequest(client, req)
+}
+
+// loadRobots returns the cached rules of the request's site, fetching its
+// robots.txt when missing or expired.
+func loadRobots(client *http.Client, req *http.Request) (*robotsRules, error) {
+	site := req.URL.Scheme + "://" + req.URL.Host
+
+	robotsCache.Lock()
+	rules, ok := robotsCache.byHost[site]
+	robotsCache.Unlock()
+	if ok && time.Since(rules.fetchedAt) < RobotsTTL {
+		return rules, nil
+	}
+
+	robotsReq, err := http.NewRequestWithContext(req.Context(), http.MethodGet, site+"/robots.txt", nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating robots.txt request: %w", err)
+	}
+	resp, err := sendRequest(client, robotsReq)
+	if err != nil {
+		return nil, fmt.Errorf("fetching robots.txt: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	// Per RFC 9309, a missing robots.txt allows everything, while an
+	// unreachable one disallows everything until it can be read.
+	switch {
+	case resp.StatusCode >= 500:
+		return nil, fmt.Errorf("fetching robots.txt: unexpected status: %d", resp.StatusCode)
+	case resp.StatusCode >= 400:
+		rules = &robotsRules{}
+	default:
+		rules, err = parseRobots(io.LimitReader(resp.Body, maxRobotsSize), robotsAgent())
+		if err != nil {
+			return nil, fmt.Errorf("reading robots.txt: %w", err)
+		}
+	}
+	rules.fetchedAt = time.Now()
+	if rules.crawlDelay > 0 {
+		setHostInterval(req.URL.Host, rules.crawlDelay)
+	}
+
+	robotsCache.Lock()
+	robotsCache.byHost[site] = rules
+	robotsCache.Unlock()
+	return rules, nil
+}
+
+// robotsAgent returns the product token of UserAgent, e.g. "venvi".
+func robotsAgent() string {
+	agent, _, _ := strings.Cut(UserAgent, "/")
+	agent, _, _ = strings.Cut(agent, " ")
+	return strings.ToLower(agent)
+}
+
+// parseRobots reads the rules of the group matching agent, falling back to
+// the "*" group.
+func parseRobots(r io.Reader, agent string) (*robotsRules, error) {
+	type group struct {
+		agents []string
+		rules  []robotsRule
+		delay  time.Duration
+	}
+	var groups []*group
+	var current *group
+	inAgents := false
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		switch key {
+		case "user-agent":
+			// Consecutive user-agent lines share one group
+			if !inAgents {
+				current = &group{}
+				groups = append(groups, current)
+			}
+			current.agents = append(current.agents, strings.ToLower(value))
+			inAgents = true
+		case "allow", "disallow":
+			inAgents = false
+			if current == nil || value == "" {
+				continue
+			}
+			current.rules = append(current.rules, robotsRule{
+				allow:   key == "allow",
+				length:  len(value),
+				pattern: robotsPattern(value),
+			})
+		case "crawl-delay":
+			inAgents = false
+			if current == nil {
+				continue
+			}
+			if seconds, err := strconv.ParseFloat(value, 64); err == nil && seconds > 0 {
+				current.delay = time.Duration(seconds * float64(time.Second))
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	var matched, fallback []*group
+	for _, g := range groups {
+		for _, a := range g.agents {
+			switch a {
+			case agent:
+				matched = append(matched, g)
+			case "*":
+				fallback = append(fallback, g)
+			}
+		}
+	}
+	if len(matched) == 0 {
+		matched = fallback
+	}
+
+	rules := &robotsRules{}
+	for _, g := range matched {
+		rules.rules = append(rules.rules, g.rules...)
+		if g.delay > rules.crawlDelay {
+			rules.crawlDelay = g.delay
+		}
+	}
+	return rules, nil
+}
+
+// robotsPattern compiles a path pattern, where "*" matches any characters
+// and a trailing "$" anchors the end.
+func robotsPattern(value string) *regexp.Regexp {
+	anchored := strings.HasSuffix(value, "$")
+	value = strings.TrimSuffix(value, "$")
+	pattern := "^" + strings.ReplaceAll(regexp.QuoteMeta(value), `\*`, ".*")
+	if anchored {
+		pattern += "$"
+	}
+	return regexp.MustCompile(pattern)
+}
+
+// allowed reports whether u may be fetched. The longest matching rule wins;
+// on a tie, Allow wins.
+func (r *robotsRules) allowed(u *url.URL) bool {
+	path := u.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	if u.RawQuery != "" {
+		path += "?" + u.RawQuery
+	}
+
+	allowed, length := true, -1
+	for _, rule := range r.rules {
+		if !rule.pattern.MatchString(path) {
+			continue
+		}
+		if rule.length > length || (rule.length == length && rule.allow) {
+			allowed, length = rule.allow, rule.length
+		}
+	}
+	return allowed
+}
@@ -0,0 +1,74 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRobots(t *testing.T) {
+	robots := `# comment
+User-agent: *
+Disallow: /
+
+User-agent: other
+User-agent: venvi
+Disallow: /private
+Allow: /private/events
+Disallow: /*.pdf$
+Crawl-delay: 2.5
+`
+	rules, err := parseRobots(strings.NewReader(robots), "venvi")
+	require.NoError(t, err)
+	assert.Equal(t, 2500*time.Millisecond, rules.crawlDelay)
+
+	for path, allowed := range map[string]bool{
+		"/":                             true,
+		"/events":                       true,
+		"/private":                      false,
+		"/private/page":                 false,
+		"/private/events/1":             true,
+		"/files/program.pdf":            false,
+		"/files/program.pdf?download=1": true,
+	} {
+		u, err := url.Parse("https://example.com" + path)
+		require.NoError(t, err)
+		assert.Equal(t, allowed, rules.allowed(u), path)
+	}
+
+	// Other agents fall back to the "*" group
+	rules, err = parseRobots(strings.NewReader(robots), "somebot")
+	require.NoError(t, err)
+	u, _ := url.Parse("https://example.com/events")
+	assert.False(t, rules.allowed(u))
+}
+
+func TestDoScraperRequest_Robots(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" {
+			_, _ = w.Write([]byte("User-agent: *\nDisallow: /admin\n"))
+			return
+		}
+		_, _ = w.Write([]byte("<html></html>"))
+	}))
+	defer server.Close()
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, server.URL+"/events", nil)
+	require.NoError(t, err)
+	resp, err := doScraperRequest(server.Client(), req)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+
+	req, err = http.NewRequestWithContext(context.Background(), http.MethodGet, server.URL+"/admin/events", nil)
+	require.NoError(t, err)
+	_, err = doScraperRequest(server.Client(), req)
+	assert.True(t, errors.Is(err, ErrDisallowedByRobots))
+}
@@ -0,0 +1,55 @@
+package providers
+
+import (
+	"log"
+	"strings"
+
+	"github.com/pocketbase/pocketbase/core"
+)
+
+// DefaultSyncSchedule is the cron expression used by providers without
+// their own schedule.
+const DefaultSyncSchedule = "0 */6 * * *"
+
+// syncJobPrefix prefixes the IDs of the per-provider cron jobs.
+const syncJobPrefix = "sync_"
+
+// RegisterSyncJobs registers one cron job per active provider, named
+// "sync_<source_name>", so that each source is polled on its own schedule.
+// It can be called again after the configuration changes: jobs of providers
+// that are no longer active are removed and the others are replaced.
+func RegisterSyncJobs(app core.App) error {
+	active, err := loadActiveProviders(app)
+	if err != nil {
+		return err
+	}
+
+	wanted := make(map[string]bool, len(active))
+	for _, p := range active {
+		wanted[syncJobPrefix+p.SourceName()] = true
+	}
+	for _, job := range app.Cron().Jobs() {
+		if strings.HasPrefix(job.Id(), syncJobPrefix) && !wanted[job.Id()] {
+			app.Cron().Remove(job.Id())
+		}
+	}
+
+	for _, p := range active {
+		provider := p
+		err := app.Cron().Add(syncJobPrefix+provider.SourceName(), provider.Schedule, func() {
+			// While another sync runs, the provider is queued behind it
+			job, _, err := startSyncJob(app, []activeProvider{provider}, false)
+			if err != nil {
+				log.Printf("Skipping scheduled sync of %s: %v", provider.SourceName(), err)
+				return
+			}
+			log.Printf("Running scheduled sync of %s (job %s)...", provider.SourceName(), job.ID)
+			stats := job.Wait()
+			log.Printf("Sync of %s complete: %+v", provider.SourceName(), stats[provider.SourceName()])
+		})
+		if err != nil {
+			log.Printf("Provider %s: registering sync job: %v", provider.SourceName(), err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,523 @@
+package providers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"html"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// SchemaOrgPage describes a single web page scanned by SchemaOrgProvider.
+type SchemaOrgPage struct {
+	// URL is the address of the page publishing structured event data.
+	URL string
+	// SourceName is stored on every event found on this page.
+	SourceName string
+	// Category is used for all events of this page.
+	Category string
+}
+
+// SchemaOrgProvider extracts schema.org Event objects from JSON-LD blocks and
+// microdata embedded in web pages. It is meant for sites that already publish
+// structured data, so that no site-specific CSS selectors are needed.
+type SchemaOrgProvider struct {
+	// Name is the identifier returned by SourceName.
+	Name string
+	// Pages is the list of pages to scan.
+	Pages []SchemaOrgPage
+	// TimeZone is used for dates that carry no zone information.
+	TimeZone *time.Location
+	// Client is the HTTP client used for requests.
+	Client *http.Client
+}
+
+// NewSchemaOrgProvider creates a new SchemaOrgProvider for the given pages.
+func NewSchemaOrgProvider(pages ...SchemaOrgPage) *SchemaOrgProvider {
+	return &SchemaOrgProvider{
+		Name:     "schemaorg",
+		Pages:    pages,
+		TimeZone: time.UTC,
+		Client:   SharedClient,
+	}
+}
+
+// SourceName returns the unique identifier for this provider.
+func (p *SchemaOrgProvider) SourceName() string {
+	return p.Name
+}
+
+// FetchEvents downloads every configured page and returns one raw event per
+// schema.org Event found. A failing page is logged and skipped unless all fail.
+func (p *SchemaOrgProvider) FetchEvents(ctx context.Context) ([]RawEvent, error) {
+	var events []RawEvent
+	var lastErr error
+	failed := 0
+
+	for _, page := range p.Pages {
+		pageEvents, err := p.fetchPage(ctx, page)
+		if err != nil {
+			log.Printf("SchemaOrg: failed to fetch %s: %v", page.URL, err)
+			markPartial(ctx)
+			lastErr = err
+			failed++
+			continue
+		}
+		events = append(events, pageEvents...)
+	}
+
+	if failed > 0 && failed == len(p.Pages) {
+		return nil, fmt.Errorf("all pages failed: %w", lastErr)
+	}
+	return events, nil
+}
+
+// fetchPage downloads a page and extracts its events, following rel="next"
+// links to further listing pages.
+func (p *SchemaOrgProvider) fetchPage(ctx context.Context, page SchemaOrgPage) ([]RawEvent, error) {
+	paginator := Paginator{Name: p.SourceName(), Strategy: PaginateHTML, Scraper: true}
+	return paginator.Fetch(ctx, p.Client, page.URL, func(resp *http.Response, pageURL string) ([]RawEvent, string, error) {
+		doc, err := goquery.NewDocumentFromReader(resp.Body)
+		if err != nil {
+			return nil, "", fmt.Errorf("parsing HTML: %w", err)
+		}
+
+		objects := extractJSONLDEvents(doc)
+		objects = append(objects, extractMicrodataEvents(doc)...)
+
+		events := make([]RawEvent, 0, len(objects))
+		for _, obj := range objects {
+			raw := RawEvent(obj)
+			raw["_source_name"] = page.SourceName
+			raw["_category"] = page.Category
+			raw["_page_url"] = pageURL
+			events = append(events, raw)
+		}
+
+		next, _ := doc.Find(`link[rel="next"], a[rel="next"]`).First().Attr("href")
+		return events, next, nil
+	})
+}
+
+// extractJSONLDEvents decodes every JSON-LD script block in the document and
+// collects the Event objects, descending into arrays and @graph containers.
+func extractJSONLDEvents(doc *goquery.Document) []map[string]any {
+	var events []map[string]any
+	doc.Find(`script[type="application/ld+json"]`).Each(func(_ int, s *goquery.Selection) {
+		var data any
+		if err := json.Unmarshal([]byte(strings.TrimSpace(s.Text())), &data); err != nil {
+			log.Printf("SchemaOrg: skipping invalid JSON-LD block: %v", err)
+			return
+		}
+		events = append(events, collectSchemaEvents(data)...)
+	})
+	return events
+}
+
+// collectSchemaEvents walks a decoded JSON-LD value and returns Event objects.
+// Nested events (e.g. subEvent) are not collected separately.
+func collectSchemaEvents(data any) []map[string]any {
+	switch v := data.(type) {
+	case []any:
+		var events []map[string]any
+		for _, item := range v {
+			events = append(events, collectSchemaEvents(item)...)
+		}
+		return events
+	case map[string]any:
+		if isSchemaEventType(v["@type"]) {
+			return []map[string]any{v}
+		}
+		if graph, ok := v["@graph"]; ok {
+			return collectSchemaEvents(graph)
+		}
+		// Pages sometimes wrap events in an ItemList.
+		if items, ok := v["itemListElement"]; ok {
+			return collectSchemaEvents(items)
+		}
+		if item, ok := v["item"]; ok {
+			return collectSchemaEvents(item)
+		}
+	}
+	return nil
+}
+
+// isSchemaEventType reports whether a @type (string or list) denotes
+// schema.org/Event or one of its subtypes such as MusicEvent or Festival.
+func isSchemaEventType(t any) bool {
+	switch v := t.(type) {
+	case string:
+		name := v
+		if i := strings.LastIndexAny(name, "/:"); i >= 0 {
+			name = name[i+1:]
+		}
+		return strings.HasSuffix(name, "Event") || name == "Festival"
+	case []any:
+		for _, item := range v {
+			if isSchemaEventType(item) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// extractMicrodataEvents collects top-level microdata items whose itemtype is
+// a schema.org Event and converts them to the same shape as JSON-LD objects.
+func extractMicrodataEvents(doc *goquery.Document) []map[string]any {
+	var events []map[string]any
+	doc.Find("[itemscope][itemtype]").Each(func(_ int, s *goquery.Selection) {
+		itemType, _ := s.Attr("itemtype")
+		if !isSchemaEventType(itemType) {
+			return
+		}
+		// Skip events nested inside another item; they are read as properties.
+		if s.Parent().Closest("[itemscope]").Length() > 0 {
+			return
+		}
+		events = append(events, readMicrodataItem(s))
+	})
+	return events
+}
+
+// readMicrodataItem converts an itemscope element into a map of its properties.
+func readMicrodataItem(item *goquery.Selection) map[string]any {
+	itemType, _ := item.Attr("itemtype")
+	obj := map[string]any{"@type": itemType}
+
+	var walk func(*goquery.Selection)
+	walk = func(parent *goquery.Selection) {
+		parent.Children().Each(func(_ int, child *goquery.Selection) {
+			prop, hasProp := child.Attr("itemprop")
+			_, isScope := child.Attr("itemscope")
+
+			if hasProp {
+				var value any
+				if isScope {
+					value = readMicrodataItem(child)
+				} else {
+					value = microdataValue(child)
+				}
+				for _, name := range strings.Fields(prop) {
+					if existing, ok := obj[name]; ok {
+						if list, ok := existing.([]any); ok {
+							obj[name] = append(list, value)
+						} else {
+							obj[name] = []any{existing, value}
+						}
+					} else {
+						obj[name] = value
+					}
+				}
+			}
+
+			// Properties inside a nested item belong to that item.
+			if !isScope {
+				walk(child)
+			}
+		})
+	}
+	walk(item)
+	return obj
+}
+
+// microdataValue returns the value of an itemprop element per the HTML spec.
+func microdataValue(s *goquery.Selection) string {
+	attrs := map[string]string{
+		"meta": "content", "a": "href", "link": "href", "area": "href",
+		"img": "src", "audio": "src", "video": "src", "source": "src", "iframe": "src",
+		"time": "datetime", "data": "value", "meter": "value", "object": "data",
+	}
+	if content, ok := s.Attr("content"); ok {
+		return strings.TrimSpace(content)
+	}
+	if attr, ok := attrs[goquery.NodeName(s)]; ok {
+		if v, ok := s.Attr(attr); ok {
+			return strings.TrimSpace(v)
+		}
+	}
+	return strings.Join(strings.Fields(s.Text()), " ")
+}
+
+// MapEvent converts a RawEvent into the internal Event structure.
+func (p *SchemaOrgProvider) MapEvent(raw RawEvent) *Event {
+	title := html.UnescapeString(schemaText(raw["name"]))
+	if title == "" {
+		return nil
+	}
+
+	dateStart, ok := parseFlexibleTime(schemaText(raw["startDate"]), p.TimeZone)
+	if !ok {
+		log.Printf("SchemaOrg: skipping %q, missing or invalid startDate", title)
+		return nil
+	}
+	dateEnd, ok := parseFlexibleTime(schemaText(raw["endDate"]), p.TimeZone)
+	if !ok || dateEnd.Before(dateStart) {
+		dateEnd = dateStart.Add(2 * time.Hour)
+	}
+
+	pageURL, _ := raw["_page_url"].(string)
+	link := resolveURL(pageURL, schemaText(raw["url"]))
+	if link == "" {
+		link = pageURL
+	}
+	image := resolveURL(pageURL, schemaImage(raw["image"]))
+
+	location, lat, long := schemaLocation(raw["location"])
+
+	mode := schemaText(raw["eventAttendanceMode"])
+	topics := []string{}
+	switch {
+	case strings.HasSuffix(mode, "OnlineEventAttendanceMode"):
+		topics = append(topics, "online")
+		if location == "" {
+			location = "Online"
+		}
+	case strings.HasSuffix(mode, "MixedEventAttendanceMode"):
+		topics = append(topics, "hybrid")
+	}
+
+	details := []string{}
+	if price, free := schemaOffers(raw["offers"]); free {
+		topics = append(topics, "free")
+	} else if price != "" {
+		details = append(details, "Tickets: "+price)
+	}
+	if organizer := schemaName(raw["organizer"]); organizer != "" {
+		details = append(details, "Organizer: "+organizer)
+	}
+
+	description := stripHTML(html.UnescapeString(schemaText(raw["description"])))
+	if len(details) > 0 {
+		if description != "" {
+			description += "\n\n"
+		}
+		description += strings.Join(details, "\n")
+	}
+
+	id := schemaText(raw["@id"])
+	if id == "" {
+		id = schemaText(raw["url"])
+	}
+	if id == "" {
+		hash := sha256.Sum256([]byte(pageURL + title + dateStart.String()))
+		id = hex.EncodeToString(hash[:8])
+	}
+
+	sourceName, _ := raw["_source_name"].(string)
+	if sourceName == "" {
+		sourceName = p.SourceName()
+	}
+	category, _ := raw["_category"].(string)
+	if category == "" {
+		category = "general"
+	}
+
+	return &Event{
+		ID:          id,
+		Title:       title,
+		Description: description,
+		DateStart:   dateStart,
+		DateEnd:     dateEnd,
+		Location:    location,
+		URL:         link,
+		ImageURL:    image,
+		SourceName:  sourceName,
+		SourceID:    id,
+		Topics:      topics,
+		Category:    category,
+		Status:      schemaOrgStatus(schemaText(raw["eventStatus"])),
+		Latitude:    lat,
+		Longitude:   long,
+	}
+}
+
+// schemaText returns a string property, taking the first element of lists.
+func schemaText(v any) string {
+	switch t := v.(type) {
+	case string:
+		return strings.TrimSpace(t)
+	case float64:
+		return strconv.FormatFloat(t, 'f', -1, 64)
+	case []any:
+		if len(t) > 0 {
+			return schemaText(t[0])
+		}
+	case map[string]any:
+		// Language maps such as {"@value": "..."}.
+		if val, ok := t["@value"]; ok {
+			return schemaText(val)
+		}
+	}
+	return ""
+}
+
+// schemaName returns the name of a Thing, or the value itself if it is text.
+func schemaName(v any) string {
+	switch t := v.(type) {
+	case map[string]any:
+		return schemaText(t["name"])
+	case []any:
+		if len(t) > 0 {
+			return schemaName(t[0])
+		}
+	}
+	return schemaText(v)
+}
+
+// schemaImage returns the first image URL from a URL, ImageObject or list.
+func schemaImage(v any) string {
+	switch t := v.(type) {
+	case map[string]any:
+		if u := schemaText(t["url"]); u != "" {
+			return u
+		}
+		return schemaText(t["contentUrl"])
+	case []any:
+		if len(t) > 0 {
+			return schemaImage(t[0])
+		}
+	}
+	return schemaText(v)
+}
+
+// schemaLocation turns a Place, VirtualLocation, PostalAddress, text or list
+// into a display string and coordinates.
+func schemaLocation(v any) (string, float64, float64) {
+	switch t := v.(type) {
+	case []any:
+		// Prefer the first physical place of a hybrid event.
+		for _, item := range t {
+			if loc, lat, long := schemaLocation(item); loc != "" && loc != "Online" {
+				return loc, lat, long
+			}
+		}
+		if len(t) > 0 {
+			return schemaLocation(t[0])
+		}
+		return "", 0, 0
+	case map[string]any:
+		if strings.HasSuffix(schemaText(t["@type"]), "VirtualLocation") {
+			return "Online", 0, 0
+		}
+		if strings.HasSuffix(schemaText(t["@type"]), "PostalAddress") {
+			return schemaAddress(t), 0, 0
+		}
+
+		var parts []string
+		if name := schemaText(t["name"]); name != "" {
+			parts = append(parts, name)
+		}
+		address := ""
+		if addr, ok := t["address"].(map[string]any); ok {
+			address = schemaAddress(addr)
+		} else {
+			address = schemaText(t["address"])
+		}
+		if address != "" && !strings.Contains(strings.Join(parts, ""), address) {
+			parts = append(parts, address)
+		}
+
+		var lat, long float64
+		if geo, ok := t["geo"].(map[string]any); ok {
+			lat = schemaFloat(geo["latitude"])
+			long = schemaFloat(geo["longitude"])
+		}
+		return strings.Join(parts, ", "), lat, long
+	}
+	return schemaText(v), 0, 0
+}
+
+// schemaAddress formats a PostalAddress as "street, postcode locality".
+func schemaAddress(addr map[string]any) string {
+	var parts []string
+	if street := schemaText(addr["streetAddress"]); street != "" {
+		parts = append(parts, street)
+	}
+	city := strings.TrimSpace(schemaText(addr["postalCode"]) + " " + schemaText(addr["addressLocality"]))
+	if city != "" {
+		parts = append(parts, city)
+	}
+	if len(parts) == 0 {
+		return schemaName(addr["addressCountry"])
+	}
+	return strings.Join(parts, ", ")
+}
+
+// schemaFloat reads a number that may be encoded as JSON number or string.
+func schemaFloat(v any) float64 {
+	switch t := v.(type) {
+	case float64:
+		return t
+	case string:
+		f, _ := strconv.ParseFloat(strings.TrimSpace(t), 64)
+		return f
+	}
+	return 0
+}
+
+// schemaOffers summarizes Offer objects as "12 EUR" (lowest price) and reports
+// whether the event is free.
+func schemaOffers(v any) (string, bool) {
+	var offers []map[string]any
+	switch t := v.(type) {
+	case map[string]any:
+		offers = append(offers, t)
+	case []any:
+		for _, item := range t {
+			if o, ok := item.(map[string]any); ok {
+				offers = append(offers, o)
+			}
+		}
+	}
+
+	best, currency, found := 0.0, "", false
+	for _, o := range offers {
+		priceText := schemaText(o["price"])
+		if priceText == "" {
+			priceText = schemaText(o["lowPrice"])
+		}
+		if priceText == "" {
+			continue
+		}
+		price, err := strconv.ParseFloat(strings.ReplaceAll(priceText, ",", "."), 64)
+		if err != nil {
+			continue
+		}
+		if !found || price < best {
+			best, currency, found = price, schemaText(o["priceCurrency"]), true
+		}
+	}
+	if !found {
+		return "", false
+	}
+	if best == 0 {
+		return "", true
+	}
+	return strings.TrimSpace(strconv.FormatFloat(best, 'f', -1, 64) + " " + currency), false
+}
+
+// resolveURL resolves ref against base; it returns ref unchanged if either is invalid.
+func resolveURL(base, ref string) string {
+	if ref == "" || base == "" {
+		return ref
+	}
+	b, err := url.Parse(base)
+	if err != nil {
+		return ref
+	}
+	r, err := url.Parse(ref)
+	if err != nil {
+		return ref
+	}
+	return b.ResolveReference(r).String()
+}
@@ -0,0 +1,73 @@
+package providers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemaOrgProvider_FetchEvents(t *testing.T) {
+	fixture, err := os.ReadFile("fixtures/schemaorg.html")
+	require.NoError(t, err)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" {
+			http.NotFound(w, r)
+			return
+		}
+		assert.Equal(t, "/programme", r.URL.Path)
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = w.Write(fixture)
+	}))
+	defer server.Close()
+
+	p := NewSchemaOrgProvider(SchemaOrgPage{
+		URL:        server.URL + "/programme",
+		SourceName: "stadttheater",
+		Category:   "Art & Culture",
+	})
+
+	events, err := p.FetchEvents(context.Background())
+	require.NoError(t, err)
+	// Two JSON-LD blocks with three events plus one microdata event.
+	require.Len(t, events, 4)
+
+	hamlet := p.MapEvent(events[0])
+	require.NotNil(t, hamlet)
+	assert.Equal(t, "https://theater.example.com/#event-hamlet", hamlet.SourceID)
+	assert.Equal(t, "Hamlet & Co.", hamlet.Title)
+	assert.Equal(t, "Shakespeare, reimagined.\n\nTickets: 18 EUR\nOrganizer: Vereinigte Bühnen Bozen", hamlet.Description)
+	assert.Equal(t, server.URL+"/programme/hamlet", hamlet.URL)
+	assert.Equal(t, "https://theater.example.com/img/hamlet.jpg", hamlet.ImageURL)
+	assert.Equal(t, "Stadttheater Bozen, Verdiplatz 40, 39100 Bozen", hamlet.Location)
+	assert.InDelta(t, 46.4972, hamlet.Latitude, 0.0001)
+	assert.InDelta(t, 11.3566, hamlet.Longitude, 0.0001)
+	assert.Equal(t, "stadttheater", hamlet.SourceName)
+	assert.Equal(t, "Art & Culture", hamlet.Category)
+	assert.Equal(t, time.Date(2030, 5, 10, 18, 0, 0, 0, time.UTC), hamlet.DateStart.UTC())
+	assert.Equal(t, 150*time.Minute, hamlet.DateEnd.Sub(hamlet.DateStart))
+
+	festival := p.MapEvent(events[1])
+	require.NotNil(t, festival)
+	assert.Equal(t, "Online", festival.Location)
+	assert.Equal(t, []string{"online", "free"}, festival.Topics)
+	assert.Equal(t, server.URL+"/programme", festival.URL)
+
+	cancelled := p.MapEvent(events[2])
+	require.NotNil(t, cancelled)
+	assert.Equal(t, EventCancelled, cancelled.Status)
+
+	concert := p.MapEvent(events[3])
+	require.NotNil(t, concert)
+	assert.Equal(t, "Chamber Concert", concert.Title)
+	assert.Equal(t, "Foyer, Bozen", concert.Location)
+	assert.Equal(t, server.URL+"/img/chamber.jpg", concert.ImageURL)
+	assert.Equal(t, "https://theater.example.com/programme/chamber", concert.SourceID)
+	assert.Equal(t, time.Date(2030, 7, 4, 19, 30, 0, 0, time.UTC), concert.DateStart)
+}
@@ -0,0 +1,342 @@
+package providers
+
+import (
+	"context"
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// builtinScrapers holds the definitions of the scrapers shipped with Venvi.
+//
+//go:embed scrapers/*.json
+var builtinScrapers embed.FS
+
+// ScraperDefinition declares how to scrape events from an HTML listing page.
+// It is usually loaded from a JSON file, so that a new source needs no Go code.
+type ScraperDefinition struct {
+	// SourceName is the unique identifier of the source, e.g. "unibz".
+	SourceName string `json:"source_name"`
+	// URL is the first listing page to fetch.
+	URL string `json:"url"`
+	// ItemSelector matches one element per event on the listing page.
+	ItemSelector string `json:"item_selector"`
+	// Fields maps event fields (title, link, description, image, location,
+	// date_start, date_end) to selectors relative to the item element.
+	Fields map[string]FieldSelector `json:"fields"`
+	// DateLayouts are Go time layouts tried, in order, for date fields.
+	DateLayouts []string `json:"date_layouts,omitempty"`
+	// TimeZone is the IANA zone of dates without zone information (default UTC).
+	TimeZone string `json:"time_zone,omitempty"`
+	// NextPageSelector matches the link to the next listing page, if any.
+	NextPageSelector string `json:"next_page_selector,omitempty"`
+	// MaxPages caps pagination (default 10).
+	MaxPages int `json:"max_pages,omitempty"`
+	// IDPrefix is prepended to the source ID derived from the event link.
+	IDPrefix string `json:"id_prefix,omitempty"`
+	// Location is used when the location field is missing or empty.
+	Location string `json:"location,omitempty"`
+	// Category is stored on every event of this source.
+	Category string `json:"category,omitempty"`
+	// DefaultDuration is used when no end date is found, e.g. "2h" (default 2h).
+	DefaultDuration string `json:"default_duration,omitempty"`
+}
+
+// FieldSelector describes how to extract a single value from an item.
+type FieldSelector struct {
+	// Selector is a CSS selector relative to the item; empty means the item itself.
+	Selector string `json:"selector,omitempty"`
+	// Attr reads an attribute instead of the text content, e.g. "href".
+	Attr string `json:"attr,omitempty"`
+	// Regex optionally narrows the value. If it has capture groups, the
+	// groups are joined with single spaces; otherwise the whole match is used.
+	Regex string `json:"regex,omitempty"`
+	// Required drops items for which this field is empty.
+	Required bool `json:"required,omitempty"`
+}
+
+// urlFields are resolved against the page URL when relative.
+var urlFields = map[string]bool{"link": true, "image": true}
+
+// SelectorScraperProvider scrapes events from HTML pages according to a
+// ScraperDefinition.
+type SelectorScraperProvider struct {
+	// Definition describes the source.
+	Definition ScraperDefinition
+	// BaseURL overrides Definition.URL, e.g. for testing.
+	BaseURL string
+	// Client is the HTTP client used for requests.
+	Client *http.Client
+
+	regexes  map[string]*regexp.Regexp
+	location *time.Location
+	duration time.Duration
+}
+
+// NewSelectorScraperProvider validates a definition and creates a provider for it.
+func NewSelectorScraperProvider(def ScraperDefinition) (*SelectorScraperProvider, error) {
+	if def.SourceName == "" {
+		return nil, fmt.Errorf("scraper definition: source_name is required")
+	}
+	if def.URL == "" {
+		return nil, fmt.Errorf("scraper %s: url is required", def.SourceName)
+	}
+	if def.ItemSelector == "" {
+		return nil, fmt.Errorf("scraper %s: item_selector is required", def.SourceName)
+	}
+	if _, ok := def.Fields["title"]; !ok {
+		return nil, fmt.Errorf("scraper %s: a title field is required", def.SourceName)
+	}
+
+	p := &SelectorScraperProvider{
+		Definition: def,
+		BaseURL:    def.URL,
+		Client:     SharedClient,
+		regexes:    make(map[string]*regexp.Regexp),
+		location:   time.UTC,
+		duration:   2 * time.Hour,
+	}
+
+	for name, field := range def.Fields {
+		if field.Regex == "" {
+			continue
+		}
+		re, err := regexp.Compile(field.Regex)
+		if err != nil {
+			return nil, fmt.Errorf("scraper %s: field %s: %w", def.SourceName, name, err)
+		}
+		p.regexes[name] = re
+	}
+
+	if def.TimeZone != "" {
+		loc, err := time.LoadLocation(def.TimeZone)
+		if err != nil {
+			return nil, fmt.Errorf("scraper %s: time_zone: %w", def.SourceName, err)
+		}
+		p.location = loc
+	}
+
+	if def.DefaultDuration != "" {
+		d, err := time.ParseDuration(def.DefaultDuration)
+		if err != nil {
+			return nil, fmt.Errorf("scraper %s: default_duration: %w", def.SourceName, err)
+		}
+		p.duration = d
+	}
+
+	return p, nil
+}
+
+// ParseScraperDefinition decodes a JSON scraper definition and creates a provider.
+func ParseScraperDefinition(data []byte) (*SelectorScraperProvider, error) {
+	var def ScraperDefinition
+	if err := json.Unmarshal(data, &def); err != nil {
+		return nil, fmt.Errorf("decoding scraper definition: %w", err)
+	}
+	return NewSelectorScraperProvider(def)
+}
+
+// builtinScraper loads one of the embedded scraper definitions.
+func builtinScraper(name string) (*SelectorScraperProvider, error) {
+	data, err := builtinScrapers.ReadFile("scrapers/" + name + ".json")
+	if err != nil {
+		return nil, fmt.Errorf("builtin scraper %s: %w", name, err)
+	}
+	p, err := ParseScraperDefinition(data)
+	if err != nil {
+		return nil, fmt.Errorf("builtin scraper %s: %w", name, err)
+	}
+	return p, nil
+}
+
+// SourceName returns the unique identifier for this provider.
+func (p *SelectorScraperProvider) SourceName() string {
+	return p.Definition.SourceName
+}
+
+// FetchEvents fetches the listing page(s) and extracts one raw event per item.
+func (p *SelectorScraperProvider) FetchEvents(ctx context.Context) ([]RawEvent, error) {
+	maxPages := p.Definition.MaxPages
+	if maxPages <= 0 {
+		maxPages = 10
+	}
+
+	paginator := Paginator{
+		Name:     p.SourceName(),
+		Strategy: PaginateHTML,
+		MaxPages: maxPages,
+		Scraper:  true,
+	}
+	events, err := paginator.Fetch(ctx, p.Client, p.BaseURL, p.parsePage)
+	if err != nil {
+		if len(events) == 0 {
+			return nil, err
+		}
+		// Keep what we have if a later page fails.
+		log.Printf("Scraper %s: stopping pagination: %v", p.SourceName(), err)
+		markPartial(ctx)
+	}
+
+	if len(events) == 0 {
+		// Scrapers are fragile, log when potential structure change occurs.
+		log.Printf("Warning: %s scraper found 0 events at %s (check for DOM structure changes)", p.SourceName(), p.BaseURL)
+	}
+
+	return events, nil
+}
+
+// parsePage extracts the items of a listing page and its next page link.
+func (p *SelectorScraperProvider) parsePage(resp *http.Response, pageURL string) ([]RawEvent, string, error) {
+	doc, err := goquery.NewDocumentFromReader(resp.Body)
+	if err != nil {
+		return nil, "", fmt.Errorf("parsing HTML: %w", err)
+	}
+
+	var events []RawEvent
+	doc.Find(p.Definition.ItemSelector).Each(func(_ int, s *goquery.Selection) {
+		if raw := p.extractItem(s, pageURL); raw != nil {
+			events = append(events, raw)
+		}
+	})
+
+	var next string
+	if p.Definition.NextPageSelector != "" {
+		next, _ = doc.Find(p.Definition.NextPageSelector).First().Attr("href")
+	}
+	return events, next, nil
+}
+
+// extractItem reads all configured fields of an item. It returns nil if a
+// required field is empty.
+func (p *SelectorScraperProvider) extractItem(s *goquery.Selection, pageURL string) RawEvent {
+	raw := RawEvent{"page_url": pageURL}
+
+	for name, field := range p.Definition.Fields {
+		sel := s
+		if field.Selector != "" {
+			sel = s.Find(field.Selector).First()
+		}
+
+		var value string
+		if field.Attr != "" {
+			value, _ = sel.Attr(field.Attr)
+		} else {
+			value = strings.Join(strings.Fields(sel.Text()), " ")
+		}
+		value = strings.TrimSpace(value)
+
+		if re := p.regexes[name]; re != nil && value != "" {
+			m := re.FindStringSubmatch(value)
+			switch {
+			case m == nil:
+				value = ""
+			case len(m) > 1:
+				value = strings.Join(strings.Fields(strings.Join(m[1:], " ")), " ")
+			default:
+				value = m[0]
+			}
+		}
+
+		if urlFields[name] && value != "" {
+			value = resolveURL(pageURL, value)
+		}
+
+		if field.Required && value == "" {
+			return nil
+		}
+		raw[name] = value
+	}
+
+	return raw
+}
+
+// MapEvent converts a RawEvent into the internal Event structure.
+// Items whose start date cannot be parsed are dropped.
+func (p *SelectorScraperProvider) MapEvent(raw RawEvent) *Event {
+	title, _ := raw["title"].(string)
+	if title == "" {
+		return nil
+	}
+	link, _ := raw["link"].(string)
+	pageURL, _ := raw["page_url"].(string)
+
+	startText, _ := raw["date_start"].(string)
+	dateStart, ok := p.parseDate(startText)
+	if !ok {
+		log.Printf("Scraper %s: skipping %q, cannot parse date %q", p.SourceName(), title, startText)
+		return nil
+	}
+	endText, _ := raw["date_end"].(string)
+	dateEnd, ok := p.parseDate(endText)
+	if !ok || dateEnd.Before(dateStart) {
+		dateEnd = dateStart.Add(p.duration)
+	}
+
+	// Derive a stable ID from the last path segment of the link.
+	id := ""
+	if normalized := strings.TrimRight(link, "/"); normalized != "" {
+		id = path.Base(normalized)
+	}
+	if id == "" || id == "." || id == "/" || strings.Contains(id, ":") {
+		hash := sha256.Sum256([]byte(link + title))
+		id = hex.EncodeToString(hash[:8])
+	}
+	id = p.Definition.IDPrefix + id
+
+	if link == "" {
+		link = pageURL
+	}
+
+	location, _ := raw["location"].(string)
+	if location == "" {
+		location = p.Definition.Location
+	}
+	category := p.Definition.Category
+	if category == "" {
+		category = "general"
+	}
+	description, _ := raw["description"].(string)
+	image, _ := raw["image"].(string)
+
+	return &Event{
+		ID:          id,
+		Title:       title,
+		Description: description,
+		DateStart:   dateStart,
+		DateEnd:     dateEnd,
+		Location:    location,
+		URL:         link,
+		ImageURL:    image,
+		SourceName:  p.SourceName(),
+		SourceID:    id,
+		Category:    category,
+		Topics:      []string{},
+	}
+}
+
+// parseDate tries the configured layouts, then common machine formats, then
+// free-text extraction.
+func (p *SelectorScraperProvider) parseDate(s string) (time.Time, bool) {
+	if s == "" {
+		return time.Time{}, false
+	}
+	for _, layout := range p.Definition.DateLayouts {
+		if t, err := time.ParseInLocation(layout, s, p.location); err == nil {
+			return t, true
+		}
+	}
+	if t, ok := parseFlexibleTime(s, p.location); ok {
+		return t, true
+	}
+	return extractDate(s, time.Now(), p.location)
+}
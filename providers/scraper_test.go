@@ -0,0 +1,98 @@
+package providers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Builtin definitions are only loaded at startup, so check all of them here
+func TestBuiltinScrapers(t *testing.T) {
+	entries, err := builtinScrapers.ReadDir("scrapers")
+	require.NoError(t, err)
+	require.NotEmpty(t, entries)
+
+	for _, entry := range entries {
+		name := strings.TrimSuffix(entry.Name(), ".json")
+		p, err := builtinScraper(name)
+		require.NoError(t, err, name)
+		assert.Equal(t, name, p.SourceName())
+	}
+}
+
+func TestSelectorScraperProvider_BuiltinDates(t *testing.T) {
+	fixture, err := os.ReadFile("fixtures/unibz.html")
+	require.NoError(t, err)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write(fixture)
+	}))
+	defer server.Close()
+
+	p, err := NewUnibzProvider()
+	require.NoError(t, err)
+	p.BaseURL = server.URL + "/en/events/"
+
+	events, err := p.FetchEvents(context.Background())
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+
+	mapped := p.MapEvent(events[0])
+	require.NotNil(t, mapped)
+	assert.Equal(t, "unibz-gennext-2026", mapped.SourceID)
+	assert.Equal(t, server.URL+"/en/events/gennext-2026", mapped.URL)
+	assert.Equal(t, time.Date(2026, 2, 12, 16, 0, 0, 0, time.UTC), mapped.DateStart)
+	assert.Equal(t, time.Date(2026, 2, 12, 18, 0, 0, 0, time.UTC), mapped.DateEnd)
+	assert.Equal(t, "Education", mapped.Category)
+	assert.Equal(t, "unibz Bolzano", mapped.Location)
+}
+
+func TestSelectorScraperProvider_Pagination(t *testing.T) {
+	pages := map[string]string{
+		"/list": `<ul>
+			<li class="ev"><a href="/e/one">One</a><time datetime="2030-01-01T10:00:00">1 Jan</time></li>
+			</ul><a class="next" href="/list?page=2">Next</a>`,
+		"/list?page=2": `<ul>
+			<li class="ev"><a href="/e/two">Two</a><time datetime="2030-01-02T10:00:00">2 Jan</time></li>
+			<li class="ev"><span>No title</span></li>
+			</ul><a class="next" href="/list">Back to start</a>`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(pages[r.URL.RequestURI()]))
+	}))
+	defer server.Close()
+
+	p, err := ParseScraperDefinition([]byte(`{
+		"source_name": "example",
+		"url": "` + server.URL + `/list",
+		"item_selector": ".ev",
+		"fields": {
+			"title": {"selector": "a", "required": true},
+			"link": {"selector": "a", "attr": "href"},
+			"date_start": {"selector": "time", "attr": "datetime"}
+		},
+		"next_page_selector": "a.next",
+		"time_zone": "Europe/Rome",
+		"category": "Community"
+	}`))
+	require.NoError(t, err)
+
+	events, err := p.FetchEvents(context.Background())
+	require.NoError(t, err)
+	require.Len(t, events, 2, "the untitled item is dropped and the loop back to page 1 is ignored")
+
+	two := p.MapEvent(events[1])
+	require.NotNil(t, two)
+	assert.Equal(t, "Two", two.Title)
+	assert.Equal(t, "two", two.SourceID)
+	assert.Equal(t, server.URL+"/e/two", two.URL)
+	assert.Equal(t, time.Date(2030, 1, 2, 9, 0, 0, 0, time.UTC), two.DateStart.UTC())
+	assert.Equal(t, 2*time.Hour, two.DateEnd.Sub(two.DateStart))
+}
@@ -0,0 +1,93 @@
+package providers
+
+import (
+	"strings"
+	"time"
+)
+
+// Event statuses. The lifecycle statuses are derived by the sync from source
+// signals and date shifts; EventRemoved is set by reconcileEvents.
+const (
+	// EventScheduled is an event taking place as announced.
+	EventScheduled = "scheduled"
+	// EventRescheduled is an event whose date changed since it was first
+	// fetched, or that its source marks as rescheduled.
+	EventRescheduled = "rescheduled"
+	// EventPostponed is an event put off to a date not yet known.
+	EventPostponed = "postponed"
+	// EventCancelled is an event that will not take place.
+	EventCancelled = "cancelled"
+	// EventRemoved is an upcoming event its source no longer lists. Removed
+	// events are hidden, and restored if the source lists them again.
+	EventRemoved = "removed"
+)
+
+// statusKeywords map words announcing a status in titles, in the languages
+// of the sources, to that status.
+var statusKeywords = map[string]string{
+	"cancelled":  EventCancelled,
+	"canceled":   EventCancelled,
+	"annullato":  EventCancelled,
+	"annullata":  EventCancelled,
+	"abgesagt":   EventCancelled,
+	"postponed":  EventPostponed,
+	"rinviato":   EventPostponed,
+	"rinviata":   EventPostponed,
+	"verschoben": EventPostponed,
+}
+
+// statusFromText returns the status a title announces, such as
+// "ANNULLATO - Concerto", or "" if it announces none.
+func statusFromText(text string) string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !('a' <= r && r <= 'z')
+	})
+	for _, word := range words {
+		if status, ok := statusKeywords[word]; ok {
+			return status
+		}
+	}
+	return ""
+}
+
+// schemaOrgStatus maps a schema.org eventStatus, such as
+// "https://schema.org/EventCancelled", to a status, or "" if unknown.
+func schemaOrgStatus(eventStatus string) string {
+	i := strings.LastIndexAny(eventStatus, "/:")
+	switch eventStatus[i+1:] {
+	case "EventCancelled":
+		return EventCancelled
+	case "EventPostponed":
+		return EventPostponed
+	case "EventRescheduled":
+		return EventRescheduled
+	case "EventScheduled", "EventMovedOnline":
+		return EventScheduled
+	}
+	return ""
+}
+
+// resolveStatus sets the status of a mapped event without one from the
+// keywords of its title, defaulting to EventScheduled.
+func resolveStatus(event *Event) {
+	if event.Status != "" {
+		return
+	}
+	event.Status = statusFromText(event.Title)
+	if event.Status == "" {
+		event.Status = EventScheduled
+	}
+}
+
+// lifecycleStatus returns the status to store for an event the source lists
+// with status, whose record had previous status and start date. A scheduled
+// event whose date moved is rescheduled, and stays so.
+func lifecycleStatus(status, previous string, previousStart, start time.Time) string {
+	if status != EventScheduled {
+		return status
+	}
+	if previous == EventRescheduled || !previousStart.IsZero() && !previousStart.Equal(start.Truncate(time.Millisecond)) {
+		return EventRescheduled
+	}
+	return status
+}
@@ -0,0 +1,33 @@
+package providers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatusFromText(t *testing.T) {
+	assert.Equal(t, EventCancelled, statusFromText("ANNULLATO - Concerto d'estate"))
+	assert.Equal(t, EventCancelled, statusFromText("Lesung (abgesagt)"))
+	assert.Equal(t, EventCancelled, statusFromText("Cancelled: Go Meetup"))
+	assert.Equal(t, EventPostponed, statusFromText("Jazz night - verschoben"))
+	assert.Equal(t, "", statusFromText("Cancellation policy workshop"))
+}
+
+func TestSchemaOrgStatus(t *testing.T) {
+	assert.Equal(t, EventCancelled, schemaOrgStatus("https://schema.org/EventCancelled"))
+	assert.Equal(t, EventPostponed, schemaOrgStatus("http://schema.org/EventPostponed"))
+	assert.Equal(t, EventRescheduled, schemaOrgStatus("EventRescheduled"))
+	assert.Equal(t, EventScheduled, schemaOrgStatus("schema:EventMovedOnline"))
+	assert.Equal(t, "", schemaOrgStatus(""))
+}
+
+func TestLifecycleStatus(t *testing.T) {
+	start := time.Date(2030, 1, 1, 18, 0, 0, 0, time.UTC)
+	assert.Equal(t, EventScheduled, lifecycleStatus(EventScheduled, "", time.Time{}, start))
+	assert.Equal(t, EventScheduled, lifecycleStatus(EventScheduled, EventScheduled, start, start.In(time.Local)))
+	assert.Equal(t, EventRescheduled, lifecycleStatus(EventScheduled, EventScheduled, start, start.Add(24*time.Hour)))
+	assert.Equal(t, EventRescheduled, lifecycleStatus(EventScheduled, EventRescheduled, start, start))
+	assert.Equal(t, EventCancelled, lifecycleStatus(EventCancelled, EventRescheduled, start, start.Add(time.Hour)))
+}
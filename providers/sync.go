@@ -4,128 +4,444 @@ package providers
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"sync"
 	"time"
 
 	"github.com/pocketbase/pocketbase/core"
 )
 
-// Providers is the list of all registered event providers.
-// Add new providers here to include them in sync operations.
-var Providers = []EventProvider{
-	NewODHProvider(),
-	NewEuroHackathonsProvider(),
-	NewDrinbzProvider(),
-	NewNOIProvider(),
-	NewUnibzProvider(),
-	NewMuseionProvider(),
+// Providers is the list of compiled-in event providers. Records of the
+// providers collection can disable, reconfigure or extend it at runtime,
+// see ActiveProviders.
+var Providers = builtinProviders()
+
+// builtinProviders creates the compiled-in providers. Builtin scrapers whose
+// definition fails to load are logged and left out.
+func builtinProviders() []EventProvider {
+	list := []EventProvider{
+		NewODHProvider(),
+		NewEuroHackathonsProvider(),
+		NewDrinbzProvider(),
+		NewNOIProvider(),
+	}
+	for _, newScraper := range []func() (*SelectorScraperProvider, error){NewUnibzProvider, NewMuseionProvider} {
+		p, err := newScraper()
+		if err != nil {
+			log.Printf("Skipping %v", err)
+			continue
+		}
+		list = append(list, p)
+	}
+	return list
 }
 
+// DefaultProviderTimeout bounds the sync of a provider without its own timeout.
+const DefaultProviderTimeout = 2 * time.Minute
+
+// SyncWorkers is the number of providers fetched concurrently.
+var SyncWorkers = 4
+
+// writeMu serializes record writes, so that concurrent syncs never race on
+// the find-then-create logic of upsertEvent.
+var writeMu sync.Mutex
+
 // SyncStats contains statistics about a sync operation.
 type SyncStats struct {
 	Provider string `json:"provider"`
 	New      int    `json:"new"`
-	Updated  int    `json:"updated"`
-	Errors   int    `json:"errors"`
+	// Updated is the number of existing events whose content changed.
+	Updated int `json:"updated"`
+	// Unchanged is the number of existing events fetched again as they were,
+	// which are not written.
+	Unchanged int `json:"unchanged"`
+	Errors    int `json:"errors"`
+	// Timeouts is 1 if the provider did not finish within its timeout.
+	Timeouts int `json:"timeouts"`
+	// Fetched is the number of raw items returned by the provider.
+	Fetched int `json:"fetched"`
+	// Mapped is the number of events mapped from the raw items.
+	Mapped int `json:"mapped"`
+	// Skipped is the number of raw items that yielded no event.
+	Skipped int `json:"skipped"`
+	// ErrorMessages holds the first maxErrorMessages errors.
+	ErrorMessages []string `json:"error_messages,omitempty"`
+	// Warnings explains why a run was marked degraded, see detectBreakage.
+	Warnings []string `json:"warnings,omitempty"`
+	// NotModified is true if every request of the provider returned 304, so
+	// its events were not written again.
+	NotModified bool `json:"not_modified,omitempty"`
+	// Incremental is true if an IncrementalProvider only fetched the items
+	// changed since its last sync.
+	Incremental bool `json:"incremental,omitempty"`
+	// Removed is the number of events marked removed, see reconcileEvents.
+	Removed int `json:"removed"`
+	// Partial is true if the fetch missed part of the source, because a feed
+	// or page failed or pagination was capped. See markPartial.
+	Partial bool `json:"partial,omitempty"`
+
+	// seen holds the source ids of the mapped events.
+	seen map[string]bool
+}
+
+// maxErrorMessages caps SyncStats.ErrorMessages.
+const maxErrorMessages = 20
+
+// see records the source ids of mapped events.
+func (s *SyncStats) see(events []*Event) {
+	if s.seen == nil {
+		s.seen = make(map[string]bool)
+	}
+	for _, event := range events {
+		s.seen[event.SourceID] = true
+	}
+}
+
+// addError counts an error and keeps its message.
+func (s *SyncStats) addError(err error) {
+	s.Errors++
+	if len(s.ErrorMessages) < maxErrorMessages {
+		s.ErrorMessages = append(s.ErrorMessages, err.Error())
+	}
 }
 
-// SyncAllEvents synchronizes events from all registered providers.
-// It fetches events from each provider, maps them to the unified format,
-// and upserts them into the PocketBase events collection.
+// SyncAllEvents synchronizes events from all active providers and waits for
+// the result. Providers are fetched concurrently by SyncWorkers workers, each
+// under its own timeout, and their events are upserted into the PocketBase
+// events collection one provider at a time. If a sync is already running in
+// this process, it is joined instead. See StartSyncJob for a sync running in
+// the background.
 func SyncAllEvents(app core.App) (map[string]SyncStats, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
-	defer cancel()
+	job, _, err := StartSyncJob(app)
+	if err != nil {
+		return nil, err
+	}
+	return job.Wait(), nil
+}
 
-	stats := make(map[string]SyncStats)
+// syncProviders syncs the given providers through a bounded worker pool,
+// reporting progress to job.
+func syncProviders(ctx context.Context, app core.App, active []activeProvider, job *SyncJob) map[string]SyncStats {
+	workers := SyncWorkers
+	if workers < 1 {
+		workers = 1
+	}
 
-	for _, provider := range Providers {
-		providerStats, err := syncProvider(ctx, app, provider)
-		if err != nil {
-			log.Printf("Error syncing %s: %v", provider.SourceName(), err)
-			stats[provider.SourceName()] = SyncStats{
-				Provider: provider.SourceName(),
-				Errors:   1,
+	jobs := make(chan activeProvider)
+	results := make(chan SyncStats, len(active))
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers && w < len(active); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for provider := range jobs {
+				results <- syncWithTimeout(ctx, app, provider, job)
 			}
-			continue
-		}
-		stats[provider.SourceName()] = providerStats
+		}()
 	}
 
-	return stats, nil
+	for _, provider := range active {
+		jobs <- provider
+	}
+	close(jobs)
+	wg.Wait()
+	close(results)
+
+	stats := make(map[string]SyncStats, len(active))
+	for s := range results {
+		stats[s.Provider] = s
+	}
+	return stats
 }
 
-// syncProvider syncs events from a single provider.
-func syncProvider(ctx context.Context, app core.App, provider EventProvider) (SyncStats, error) {
+// syncWithTimeout syncs a single provider. The provider's timeout bounds
+// fetching; a timeout is recorded separately from other failures, and a
+// cancelled sync is not counted as a failure at all.
+func syncWithTimeout(ctx context.Context, app core.App, provider activeProvider, job *SyncJob) SyncStats {
 	stats := SyncStats{Provider: provider.SourceName()}
+	if ctx.Err() != nil {
+		job.setState(ProviderCancelled, stats)
+		return stats
+	}
+
+	startedAt := time.Now()
+	job.setState(ProviderFetching, stats)
+	cache := newHTTPCache(app)
+	cursor := incrementalCursor(app, provider.EventProvider, job.Full)
+	stats.Incremental = cursor != ""
+	fetchCtx, partial := withPartial(withSyncCursor(withHTTPCache(ctx, cache), cursor))
+	fetchCtx, cancel := context.WithTimeout(fetchCtx, provider.Timeout)
+	var err error
+	if streamer, ok := provider.EventProvider.(StreamingProvider); ok {
+		stats, err = streamEvents(fetchCtx, app, provider, streamer, cache, startedAt, stats, job)
+	} else {
+		stats, err = fetchEvents(fetchCtx, ctx, app, provider, cache, startedAt, stats, job)
+	}
+	timedOut := errors.Is(fetchCtx.Err(), context.DeadlineExceeded)
+	cancel()
+	stats.Partial = partial.Load()
+
+	var state string
+	switch {
+	case err == nil:
+		state = ProviderDone
+	case ctx.Err() != nil:
+		log.Printf("Sync of %s cancelled", provider.SourceName())
+		state = ProviderCancelled
+	case timedOut || errors.Is(err, context.DeadlineExceeded):
+		log.Printf("Timeout syncing %s after %s: %v", provider.SourceName(), provider.Timeout, err)
+		stats.Timeouts = 1
+		stats.ErrorMessages = append(stats.ErrorMessages, err.Error())
+		state = ProviderTimedOut
+	default:
+		log.Printf("Error syncing %s: %v", provider.SourceName(), err)
+		stats.addError(err)
+		state = ProviderFailed
+	}
+
+	// A run without errors can still be a broken source returning nothing.
+	// Incremental runs naturally return little.
+	if state == ProviderDone && !stats.NotModified && !stats.Incremental {
+		baseline, err := loadSyncBaseline(app, provider.SourceName())
+		if err != nil {
+			log.Printf("Breakage detection for %s failed: %v", provider.SourceName(), err)
+		} else if reasons := detectBreakage(baseline, stats); len(reasons) > 0 {
+			log.Printf("Provider %s looks broken: %v", provider.SourceName(), reasons)
+			stats.Warnings = reasons
+			state = ProviderDegraded
+		}
+	}
+
+	// Only a complete fetch tells which events its source dropped
+	if state == ProviderDone && !stats.Incremental && !stats.Partial && stats.Errors == 0 {
+		if stats.NotModified {
+			err = touchSeenEvents(app, provider.SourceName(), nil, startedAt)
+		} else {
+			stats.Removed, err = reconcileEvents(app, provider.SourceName(), stats.seen, startedAt)
+		}
+		if err != nil {
+			log.Printf("Reconciling events of %s failed: %v", provider.SourceName(), err)
+		}
+	}
+
+	// Degraded and partial runs keep the old cursor, so nothing is skipped
+	if state == ProviderDone && !stats.Partial {
+		saveSyncCursor(app, provider.EventProvider, startedAt, stats.Incremental)
+	}
 
-	// Fetch raw events
-	rawEvents, err := provider.FetchEvents(ctx)
+	recordSyncRun(app, job.ID, state, stats, startedAt, time.Now())
+	job.setState(state, stats)
+	return stats
+}
+
+// fetchEvents fetches all raw events of a provider under fetchCtx and then
+// writes them, unless every request was answered from the HTTP cache.
+func fetchEvents(fetchCtx, ctx context.Context, app core.App, provider EventProvider, cache *httpCache, startedAt time.Time, stats SyncStats, job *SyncJob) (SyncStats, error) {
+	rawEvents, err := provider.FetchEvents(fetchCtx)
 	if err != nil {
 		return stats, fmt.Errorf("fetching events: %w", err)
 	}
 
+	stats.Fetched = len(rawEvents)
+	if cache.unchanged() {
+		// Nothing changed upstream since the last successful sync
+		stats.NotModified = true
+		return stats, nil
+	}
+
+	archiveRawEvents(app, provider.SourceName(), job.ID, startedAt, 1, stats.Incremental, rawEvents)
+	job.setState(ProviderWriting, stats)
+	stats, err = writeEvents(ctx, app, provider, rawEvents, startedAt, stats, job)
+	// Events that failed to save must be fetched again next time
+	if err == nil && stats.Errors == 0 {
+		cache.commit()
+	}
+	return stats, err
+}
+
+// streamEvents syncs a StreamingProvider batch by batch under ctx, which
+// therefore bounds writing too. Batches written before a failure are kept.
+// Batches whose requests were all answered from the HTTP cache are not
+// written again.
+func streamEvents(ctx context.Context, app core.App, provider EventProvider, streamer StreamingProvider, cache *httpCache, startedAt time.Time, stats SyncStats, job *SyncJob) (SyncStats, error) {
+	page := 0
+	var writeErr error
+	err := streamer.StreamEvents(ctx, func(rawEvents []RawEvent) error {
+		page++
+		stats.Fetched += len(rawEvents)
+		archiveRawEvents(app, provider.SourceName(), job.ID, startedAt, page, stats.Incremental, rawEvents)
+		if cache.batchUnchanged() {
+			// Count the events anyway, so that the yield stays comparable
+			var seen []string
+			for _, raw := range rawEvents {
+				events := mapEvents(provider, raw)
+				if len(events) == 0 {
+					stats.Skipped++
+				}
+				stats.Mapped += len(events)
+				stats.Unchanged += len(events)
+				stats.see(events)
+				seen = appendSourceIDs(seen, events)
+			}
+			if len(seen) > 0 {
+				if err := touchSeenEvents(app, provider.SourceName(), seen, startedAt); err != nil {
+					log.Printf("Error refreshing events of %s: %v", provider.SourceName(), err)
+				}
+			}
+			job.setState(ProviderFetching, stats)
+			return nil
+		}
+		stats, writeErr = writeEvents(ctx, app, provider, rawEvents, startedAt, stats, job)
+		job.setState(ProviderFetching, stats)
+		return writeErr
+	})
+	switch {
+	case writeErr != nil:
+		return stats, writeErr
+	case err != nil:
+		return stats, fmt.Errorf("fetching events: %w", err)
+	}
+
+	stats.NotModified = cache.unchanged()
+	if stats.Errors == 0 {
+		cache.commit()
+	}
+	return stats, nil
+}
+
+// writeEvents maps and upserts the raw events of a provider, fetched at
+// seenAt, and refreshes last_seen_at of every event they contain. Writes
+// hold writeMu, so that only one provider writes at a time; they stop early
+// if ctx is cancelled.
+func writeEvents(ctx context.Context, app core.App, provider EventProvider, rawEvents []RawEvent, seenAt time.Time, stats SyncStats, job *SyncJob) (SyncStats, error) {
 	// Get or create events collection
 	collection, err := app.FindCollectionByNameOrId("events")
 	if err != nil {
 		return stats, fmt.Errorf("finding events collection: %w", err)
 	}
 
+	writeMu.Lock()
+	defer writeMu.Unlock()
+
 	// Process each event
+	var seen []string
 	for _, raw := range rawEvents {
-		event := provider.MapEvent(raw)
-		if event == nil {
-			continue // Skip invalid/filtered events
+		if err = ctx.Err(); err != nil {
+			break
 		}
+		events := mapEvents(provider, raw)
+		if len(events) == 0 {
+			stats.Skipped++
+		}
+		stats.Mapped += len(events)
+		stats.see(events)
+		seen = appendSourceIDs(seen, events)
+		for _, event := range events {
+			resolveStatus(event)
+			upsertEvent(app, collection, event, seenAt, &stats)
+		}
+		job.setState(ProviderWriting, stats)
+	}
 
-		// Find existing record by source_name and source_id
-		records, err := app.FindRecordsByFilter(
-			collection,
-			"source_name = {:source_name} && source_id = {:source_id}",
-			"",
-			1,
-			0,
-			map[string]any{
-				"source_name": event.SourceName,
-				"source_id":   event.SourceID,
-			},
-		)
-
-		if err != nil || len(records) == 0 {
-			// Event doesn't exist, create new
-			record := core.NewRecord(collection)
-			if err := populateRecord(record, event); err != nil {
-				log.Printf("Error populating record: %v", err)
-				stats.Errors++
-				continue
-			}
+	// Unchanged events are not written, but were seen all the same
+	if len(seen) > 0 {
+		if err := touchEvents(app, collection, provider.SourceName(), seen, seenAt); err != nil {
+			log.Printf("Error refreshing events of %s: %v", provider.SourceName(), err)
+		}
+	}
+	return stats, err
+}
 
-			if err := app.Save(record); err != nil {
-				log.Printf("Error saving new event %s/%s: %v", event.SourceName, event.SourceID, err)
-				stats.Errors++
-				continue
-			}
-			stats.New++
-		} else {
-			// Event exists, update it (but preserve is_new status)
-			existing := records[0]
-			event.IsNew = existing.GetBool("is_new")
-			if err := populateRecord(existing, event); err != nil {
-				log.Printf("Error updating record: %v", err)
-				stats.Errors++
-				continue
-			}
+// appendSourceIDs appends the source ids of events to ids.
+func appendSourceIDs(ids []string, events []*Event) []string {
+	for _, event := range events {
+		ids = append(ids, event.SourceID)
+	}
+	return ids
+}
 
-			if err := app.Save(existing); err != nil {
-				log.Printf("Error updating event %s/%s: %v", event.SourceName, event.SourceID, err)
-				stats.Errors++
-				continue
-			}
-			stats.Updated++
+// mapEvents maps a raw item to events, using MapEvents when the provider
+// implements MultiEventMapper. Invalid or filtered items yield no events.
+func mapEvents(provider EventProvider, raw RawEvent) []*Event {
+	if mapper, ok := provider.(MultiEventMapper); ok {
+		return mapper.MapEvents(raw)
+	}
+	if event := provider.MapEvent(raw); event != nil {
+		return []*Event{event}
+	}
+	return nil
+}
+
+// upsertEvent creates or updates the record for a single event seen at
+// seenAt and records the outcome in stats. A removed event is restored.
+func upsertEvent(app core.App, collection *core.Collection, event *Event, seenAt time.Time, stats *SyncStats) {
+	// Find existing record by source_name and source_id
+	records, err := app.FindRecordsByFilter(
+		collection,
+		"source_name = {:source_name} && source_id = {:source_id}",
+		"",
+		1,
+		0,
+		map[string]any{
+			"source_name": event.SourceName,
+			"source_id":   event.SourceID,
+		},
+	)
+
+	if err != nil || len(records) == 0 {
+		// Event doesn't exist, create new
+		record := core.NewRecord(collection)
+		if err := populateRecord(record, event); err != nil {
+			log.Printf("Error populating record: %v", err)
+			stats.addError(fmt.Errorf("populating %s: %w", event.SourceID, err))
+			return
+		}
+		record.Set("content_hash", contentHash(event))
+		record.Set("first_seen_at", seenAt)
+		record.Set("last_seen_at", seenAt)
+
+		if err := app.Save(record); err != nil {
+			log.Printf("Error saving new event %s/%s: %v", event.SourceName, event.SourceID, err)
+			stats.addError(fmt.Errorf("saving %s: %w", event.SourceID, err))
+			return
 		}
+		stats.New++
+		return
 	}
 
-	return stats, nil
+	// Event exists, update it
+	existing := records[0]
+	hash := contentHash(event)
+	if existing.GetString("content_hash") == hash && existing.GetString("status") != EventRemoved {
+		// writeEvents refreshes last_seen_at without a write
+		stats.Unchanged++
+		return
+	}
+	// The hash covers the status announced by the source, not the derived one
+	event.Status = lifecycleStatus(event.Status, existing.GetString("status"), existing.GetDateTime("date_start").Time(), event.DateStart)
+	if err := populateRecord(existing, event); err != nil {
+		log.Printf("Error updating record: %v", err)
+		stats.addError(fmt.Errorf("populating %s: %w", event.SourceID, err))
+		return
+	}
+	existing.Set("content_hash", hash)
+	// A remap of an older fetch does not move last_seen_at back
+	if seenAt.After(existing.GetDateTime("last_seen_at").Time()) {
+		existing.Set("last_seen_at", seenAt)
+	}
+
+	changes := diffEvent(existing)
+
+	if err := app.Save(existing); err != nil {
+		log.Printf("Error updating event %s/%s: %v", event.SourceName, event.SourceID, err)
+		stats.addError(fmt.Errorf("updating %s: %w", event.SourceID, err))
+		return
+	}
+	saveRevision(app, existing, event.SourceName, changes)
+	stats.Updated++
 }
 
 // populateRecord fills a PocketBase record with event data.
@@ -150,7 +466,7 @@ func populateRecord(record *core.Record, event *Event) error {
 	record.Set("topics", string(topicsJSON))
 
 	record.Set("category", event.Category)
-	record.Set("is_new", event.IsNew)
+	record.Set("status", event.Status)
 
 	return nil
 }
@@ -0,0 +1,216 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// TribeEventsProvider fetches events from WordPress sites running the
+// "The Events Calendar" plugin, via /wp-json/tribe/events/v1/events.
+// Unlike plain WordPress posts, these carry real start/end dates and venues.
+type TribeEventsProvider struct {
+	// Name is the identifier returned by SourceName.
+	Name string
+	// BaseURL is the events endpoint, e.g. https://example.com/wp-json/tribe/events/v1/events.
+	BaseURL string
+	// Category is used for all events; if empty the first Tribe category is used.
+	Category string
+	// Location is used when an event has no venue.
+	Location string
+	// PerPage is the page size requested from the API.
+	PerPage int
+	// MaxPages caps how many pages are followed through next_rest_url.
+	MaxPages int
+	// Client is the HTTP client used for requests.
+	Client *http.Client
+}
+
+// NewTribeEventsProvider creates a provider for the WordPress site at siteURL.
+func NewTribeEventsProvider(name, siteURL string) *TribeEventsProvider {
+	return &TribeEventsProvider{
+		Name:     name,
+		BaseURL:  strings.TrimRight(siteURL, "/") + "/wp-json/tribe/events/v1/events",
+		PerPage:  50,
+		MaxPages: 20,
+		Client:   SharedClient,
+	}
+}
+
+// SourceName returns the unique identifier for this provider.
+func (p *TribeEventsProvider) SourceName() string {
+	return p.Name
+}
+
+// tribeResponse is a single page of the Tribe events API.
+type tribeResponse struct {
+	Events      []map[string]any `json:"events"`
+	Total       int              `json:"total"`
+	TotalPages  int              `json:"total_pages"`
+	NextRestURL string           `json:"next_rest_url"`
+}
+
+// FetchEvents retrieves upcoming events, following next_rest_url until the
+// last page or MaxPages is reached.
+func (p *TribeEventsProvider) FetchEvents(ctx context.Context) ([]RawEvent, error) {
+	u, err := url.Parse(p.BaseURL)
+	if err != nil {
+		return nil, fmt.Errorf("parsing url: %w", err)
+	}
+	q := u.Query()
+	q.Set("start_date", time.Now().Format("2006-01-02"))
+	u.RawQuery = q.Encode()
+
+	paginator := Paginator{
+		Name:      p.SourceName(),
+		Strategy:  PaginateCursor,
+		SizeParam: "per_page",
+		PageSize:  p.PerPage,
+		MaxPages:  p.MaxPages,
+	}
+	return paginator.Fetch(ctx, p.Client, u.String(), decodeTribePage)
+}
+
+// decodeTribePage reads the events of a page and the URL of the next one.
+func decodeTribePage(resp *http.Response, _ string) ([]RawEvent, string, error) {
+	var result tribeResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, "", fmt.Errorf("decoding response: %w", err)
+	}
+
+	events := make([]RawEvent, 0, len(result.Events))
+	for _, item := range result.Events {
+		events = append(events, RawEvent(item))
+	}
+	return events, result.NextRestURL, nil
+}
+
+// MapEvent converts a RawEvent into the internal Event structure.
+func (p *TribeEventsProvider) MapEvent(raw RawEvent) *Event {
+	id := valueToString(raw["id"])
+	title := html.UnescapeString(valueToString(raw["title"]))
+	if id == "" || title == "" {
+		return nil
+	}
+
+	dateStart, ok := tribeDate(raw, "start_date")
+	if !ok {
+		log.Printf("Tribe %s: skipping %q, invalid start date", p.SourceName(), title)
+		return nil
+	}
+	dateEnd, ok := tribeDate(raw, "end_date")
+	if !ok || dateEnd.Before(dateStart) {
+		dateEnd = dateStart
+	}
+
+	location := p.Location
+	var lat, long float64
+	// The API returns an empty array instead of an object when there is no venue.
+	if venue, ok := raw["venue"].(map[string]any); ok {
+		var parts []string
+		for _, key := range []string{"venue", "address", "city"} {
+			if v := html.UnescapeString(valueToString(venue[key])); v != "" {
+				parts = append(parts, v)
+			}
+		}
+		if len(parts) > 0 {
+			location = strings.Join(parts, ", ")
+		}
+		lat = schemaFloat(venue["geo_lat"])
+		long = schemaFloat(venue["geo_lng"])
+	}
+
+	topics := []string{}
+	for _, key := range []string{"categories", "tags"} {
+		if terms, ok := raw[key].([]any); ok {
+			for _, term := range terms {
+				if t, ok := term.(map[string]any); ok {
+					if name := html.UnescapeString(valueToString(t["name"])); name != "" {
+						topics = append(topics, name)
+					}
+				}
+			}
+		}
+	}
+
+	category := p.Category
+	if category == "" {
+		if cats, ok := raw["categories"].([]any); ok && len(cats) > 0 {
+			if c, ok := cats[0].(map[string]any); ok {
+				category = html.UnescapeString(valueToString(c["name"]))
+			}
+		}
+	}
+	if category == "" {
+		category = "general"
+	}
+
+	description := stripHTML(valueToString(raw["description"]))
+	var details []string
+	if cost := html.UnescapeString(valueToString(raw["cost"])); cost != "" {
+		details = append(details, "Tickets: "+cost)
+	}
+	if organizers, ok := raw["organizer"].([]any); ok && len(organizers) > 0 {
+		if o, ok := organizers[0].(map[string]any); ok {
+			if name := html.UnescapeString(valueToString(o["organizer"])); name != "" {
+				details = append(details, "Organizer: "+name)
+			}
+		}
+	}
+	if len(details) > 0 {
+		if description != "" {
+			description += "\n\n"
+		}
+		description += strings.Join(details, "\n")
+	}
+
+	image := ""
+	// "image" is false when the event has no featured image.
+	if img, ok := raw["image"].(map[string]any); ok {
+		image = valueToString(img["url"])
+	}
+
+	return &Event{
+		ID:          id,
+		Title:       title,
+		Description: description,
+		DateStart:   dateStart,
+		DateEnd:     dateEnd,
+		Location:    location,
+		URL:         valueToString(raw["url"]),
+		ImageURL:    image,
+		SourceName:  p.SourceName(),
+		SourceID:    id,
+		Topics:      topics,
+		Category:    category,
+		Latitude:    lat,
+		Longitude:   long,
+	}
+}
+
+// tribeDate reads a Tribe date, preferring the utc_ variant and otherwise
+// interpreting the local value in the event's timezone.
+func tribeDate(raw RawEvent, key string) (time.Time, bool) {
+	const layout = "2006-01-02 15:04:05"
+
+	if utc := valueToString(raw["utc_"+key]); utc != "" {
+		if t, err := time.ParseInLocation(layout, utc, time.UTC); err == nil {
+			return t, true
+		}
+	}
+
+	loc := time.UTC
+	if tz := valueToString(raw["timezone"]); tz != "" {
+		if l, err := time.LoadLocation(tz); err == nil {
+			loc = l
+		}
+	}
+	t, err := time.ParseInLocation(layout, valueToString(raw[key]), loc)
+	return t, err == nil
+}
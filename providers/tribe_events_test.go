@@ -0,0 +1,63 @@
+package providers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTribeEventsProvider_FetchEvents(t *testing.T) {
+	fixture, err := os.ReadFile("fixtures/tribe_events.json")
+	require.NoError(t, err)
+
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/wp-json/tribe/events/v1/events", r.URL.Path)
+		if r.URL.Query().Get("page") == "2" {
+			// Last page: an event without venue or image, and no next link.
+			_, _ = w.Write([]byte(`{"events": [{"id": 4322, "title": "Open Studio",
+				"start_date": "2030-09-13 10:00:00", "end_date": "2030-09-13 12:00:00",
+				"timezone": "Europe/Rome", "venue": [], "image": false,
+				"url": "https://venue.example.com/event/open-studio/"}], "total": 2, "total_pages": 2}`))
+			return
+		}
+		assert.Equal(t, "50", r.URL.Query().Get("per_page"))
+		_, _ = w.Write([]byte(strings.ReplaceAll(string(fixture), "{{server}}", server.URL)))
+	}))
+	defer server.Close()
+
+	p := NewTribeEventsProvider("venue", server.URL+"/")
+	p.Location = "Bolzano"
+
+	events, err := p.FetchEvents(context.Background())
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+
+	jazz := p.MapEvent(events[0])
+	require.NotNil(t, jazz)
+	assert.Equal(t, "4321", jazz.SourceID)
+	assert.Equal(t, "Jazz & Wine Evening", jazz.Title)
+	assert.Equal(t, "Live jazz with local wines.\n\nTickets: €15\nOrganizer: Jazz Club Bozen", jazz.Description)
+	assert.Equal(t, "Batzen Häusl, Andreas-Hofer-Straße 30, Bozen", jazz.Location)
+	assert.InDelta(t, 46.4990, jazz.Latitude, 0.0001)
+	assert.InDelta(t, 11.3531, jazz.Longitude, 0.0001)
+	assert.Equal(t, "Music", jazz.Category)
+	assert.Equal(t, []string{"Music", "jazz"}, jazz.Topics)
+	assert.Equal(t, "https://venue.example.com/wp-content/uploads/jazz.jpg", jazz.ImageURL)
+	assert.Equal(t, time.Date(2030, 9, 12, 17, 30, 0, 0, time.UTC), jazz.DateStart)
+	assert.Equal(t, time.Date(2030, 9, 12, 21, 0, 0, 0, time.UTC), jazz.DateEnd)
+
+	studio := p.MapEvent(events[1])
+	require.NotNil(t, studio)
+	assert.Equal(t, "Bolzano", studio.Location)
+	assert.Equal(t, "general", studio.Category)
+	assert.Empty(t, studio.ImageURL)
+	assert.Equal(t, time.Date(2030, 9, 13, 8, 0, 0, 0, time.UTC), studio.DateStart.UTC())
+}
@@ -16,13 +16,18 @@ func TestUnibzProvider_FetchEvents(t *testing.T) {
 	require.NoError(t, err)
 
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" {
+			http.NotFound(w, r)
+			return
+		}
 		assert.Equal(t, "/en/events/", r.URL.Path)
 		w.WriteHeader(http.StatusOK)
 		_, _ = w.Write(fixture)
 	}))
 	defer server.Close()
 
-	p := NewUnibzProvider()
+	p, err := NewUnibzProvider()
+	require.NoError(t, err)
 	p.BaseURL = server.URL + "/en/events/"
 
 	events, err := p.FetchEvents(context.Background())
@@ -45,7 +50,8 @@ func TestUnibzProvider_FetchEvents_Empty(t *testing.T) {
 	}))
 	defer server.Close()
 
-	p := NewUnibzProvider()
+	p, err := NewUnibzProvider()
+	require.NoError(t, err)
 	p.BaseURL = server.URL + "/en/events/"
 
 	events, err := p.FetchEvents(context.Background())
@@ -23,6 +23,26 @@ func NewRecommendationService() *RecommendationService {
 type UserContext struct {
 	Latitude  float64
 	Longitude float64
+	// LastVisit is when a logged-in user last visited; events first seen
+	// since then are new. Zero for anonymous users and first visits.
+	LastVisit time.Time
+}
+
+// NewEventWindow is how long an event counts as new for viewers without a
+// previous visit.
+var NewEventWindow = 7 * 24 * time.Hour
+
+// IsNew reports whether the event was first seen since the user's last
+// visit, or within NewEventWindow if there is none.
+func (u UserContext) IsNew(event *providers.Event) bool {
+	if event.FirstSeenAt.IsZero() {
+		return false
+	}
+	since := u.LastVisit
+	if since.IsZero() {
+		since = time.Now().Add(-NewEventWindow)
+	}
+	return event.FirstSeenAt.After(since)
 }
 
 // ScoredEvent wraps an event with its calculated score.
@@ -46,11 +66,15 @@ const (
 )
 
 // Recommend sorts the given events based on the user's context.
-// It returns a new slice of events sorted by score (descending).
+// It returns a new slice of events sorted by score (descending), without
+// cancelled events.
 func (s *RecommendationService) Recommend(userCtx UserContext, events []providers.Event) []providers.Event {
 	scoredEvents := make([]ScoredEvent, 0, len(events))
 
 	for i := range events {
+		if events[i].Status == providers.EventCancelled {
+			continue
+		}
 		score := s.Score(userCtx, &events[i])
 		scoredEvents = append(scoredEvents, ScoredEvent{
 			Event: &events[i],
@@ -107,8 +131,8 @@ func (s *RecommendationService) Score(userCtx UserContext, event *providers.Even
 		score += WeightTime * 0.5 // Ongoing events get flat medium score
 	}
 
-	// 3. Newness Score
-	if event.IsNew {
+	// 3. Newness Score, relative to the user's last visit
+	if userCtx.IsNew(event) {
 		score += WeightNew
 	}
 
@@ -29,7 +29,6 @@ func TestScore(t *testing.T) {
 				Latitude:  40.7128,
 				Longitude: -74.0060,
 				DateStart: now.Add(24 * time.Hour), // Tomorrow
-				IsNew:     false,
 			},
 			// Distance score = 1.0 (dist=0) * 0.6 = 0.6
 			// Time score = e^(-0.01 * 24) = 0.78 * 0.3 = ~0.23
@@ -42,7 +41,6 @@ func TestScore(t *testing.T) {
 				Latitude:  34.0522,
 				Longitude: -118.2437, // LA (~4000km away)
 				DateStart: now.Add(24 * time.Hour),
-				IsNew:     false,
 			},
 			// Distance score = e^(-0.05 * 4000) ~ 0 * 0.6 = 0
 			// Time score ~ 0.23
@@ -52,10 +50,10 @@ func TestScore(t *testing.T) {
 		{
 			name: "New Event Boost",
 			event: providers.Event{
-				Latitude:  40.7128,
-				Longitude: -74.0060,
-				DateStart: now.Add(24 * time.Hour),
-				IsNew:     true,
+				Latitude:    40.7128,
+				Longitude:   -74.0060,
+				DateStart:   now.Add(24 * time.Hour),
+				FirstSeenAt: now.Add(-time.Hour),
 			},
 			// Base ~ 0.83 + 0.1
 			expected: 0.93,
@@ -102,3 +100,36 @@ func TestRecommend(t *testing.T) {
 	assert.Equal(t, "2", recommended[0].ID, "Near event should be first")
 	assert.Equal(t, "1", recommended[1].ID, "Far event should be second")
 }
+
+func TestRecommend_DropsCancelled(t *testing.T) {
+	service := NewRecommendationService()
+	now := time.Now()
+
+	events := []providers.Event{
+		{ID: "1", Title: "Cancelled Event", DateStart: now.Add(24 * time.Hour), Status: providers.EventCancelled},
+		{ID: "2", Title: "Postponed Event", DateStart: now.Add(48 * time.Hour), Status: providers.EventPostponed},
+	}
+
+	recommended := service.Recommend(UserContext{}, events)
+
+	assert.Equal(t, 1, len(recommended))
+	assert.Equal(t, "2", recommended[0].ID)
+}
+
+func TestUserContext_IsNew(t *testing.T) {
+	now := time.Now()
+	recent := &providers.Event{FirstSeenAt: now.Add(-2 * 24 * time.Hour)}
+	old := &providers.Event{FirstSeenAt: now.Add(-30 * 24 * time.Hour)}
+
+	// Anonymous users: first seen within NewEventWindow
+	anonymous := UserContext{}
+	assert.True(t, anonymous.IsNew(recent))
+	assert.False(t, anonymous.IsNew(old))
+	assert.False(t, anonymous.IsNew(&providers.Event{}))
+
+	// Logged-in users: first seen since their last visit
+	returning := UserContext{LastVisit: now.Add(-24 * time.Hour)}
+	assert.False(t, returning.IsNew(recent))
+	away := UserContext{LastVisit: now.Add(-60 * 24 * time.Hour)}
+	assert.True(t, away.IsNew(old))
+}
@@ -2,9 +2,13 @@
 package routes
 
 import (
+	"errors"
 	"log"
 	"net/http"
+	"strconv"
+	"time"
 
+	"github.com/pocketbase/pocketbase/apis"
 	"github.com/pocketbase/pocketbase/core"
 
 	"venvi/providers"
@@ -41,11 +45,11 @@ func RegisterAPIRoutes(se *core.ServeEvent, app core.App) {
 		// We fetch more events to allow the recommendation engine to re-rank them
 		limit := 500
 
-		// Default filter: future events only
+		// Default filter: future events their source still lists
 		if filter == "" {
-			filter = "date_end >= @now"
+			filter = "date_end >= @now && status != 'removed'"
 		} else {
-			filter += " && date_end >= @now"
+			filter += " && date_end >= @now && status != 'removed'"
 		}
 
 		records, err := app.FindRecordsByFilter(
@@ -79,34 +83,106 @@ func RegisterAPIRoutes(se *core.ServeEvent, app core.App) {
 		userCtx := recommendations.UserContext{
 			Latitude:  userLat,
 			Longitude: userLong,
+			LastVisit: lastVisit(e.Auth),
 		}
 		internalEvents = svc.Recommend(userCtx, internalEvents)
 
 		// Convert records to JSON-friendly format
 		// We use the helper which returns []map[string]any
-		return e.JSON(http.StatusOK, eventsToMaps(internalEvents))
+		return e.JSON(http.StatusOK, eventsToMaps(internalEvents, userCtx))
 	})
 
-	// Trigger manual sync
+	// Record a visit of the authenticated user. Pages call it on load, so
+	// that listing events stays read-only; is_new is relative to the visit
+	// before the current one.
+	se.Router.POST("/api/venvi/visits", func(e *core.RequestEvent) error {
+		previous, err := recordVisit(app, e.Auth)
+		if err != nil {
+			return e.InternalServerError("Failed to record visit", err)
+		}
+		var visit *time.Time
+		if !previous.IsZero() {
+			visit = &previous
+		}
+		return e.JSON(http.StatusOK, map[string]any{"last_visit": visit})
+	}).Bind(apis.RequireAuth())
+
+	// Start a background sync; poll /api/venvi/sync/{id} for progress.
+	// A sync of all sources already running or queued in this process is
+	// joined (200 instead of 202); while a sync of only some sources runs, the
+	// new one is queued behind it. A sync running in another process is
+	// reported with 409. With ?full=true incremental providers fetch
+	// everything instead of their changes.
 	se.Router.POST("/api/venvi/sync", func(e *core.RequestEvent) error {
-		stats, err := providers.SyncAllEvents(app)
+		start := providers.StartSyncJob
+		if full, _ := strconv.ParseBool(e.Request.URL.Query().Get("full")); full {
+			start = providers.StartFullSyncJob
+		}
+		job, joined, err := start(app)
+		var locked *providers.SyncLockedError
+		if errors.As(err, &locked) {
+			return e.JSON(http.StatusConflict, map[string]any{
+				"message": "Another sync is running",
+				"lock":    locked.Lock,
+			})
+		}
 		if err != nil {
 			return e.InternalServerError("Sync failed", err)
 		}
+		if joined {
+			return e.JSON(http.StatusOK, job.Snapshot())
+		}
+		return e.JSON(http.StatusAccepted, job.Snapshot())
+	})
 
-		// Calculate totals
-		totalNew := 0
-		totalUpdated := 0
-		for _, s := range stats {
-			totalNew += s.New
-			totalUpdated += s.Updated
+	// Changes the sync made to an event, newest first
+	se.Router.GET("/api/venvi/events/{id}/history", func(e *core.RequestEvent) error {
+		event, err := app.FindRecordById("events", e.Request.PathValue("id"))
+		if err != nil {
+			return e.NotFoundError("Event not found", err)
+		}
+		history, err := providers.EventHistory(app, event.Id)
+		if err != nil {
+			return e.InternalServerError("Failed to load event history", err)
 		}
+		return e.JSON(http.StatusOK, history)
+	})
 
-		return e.JSON(http.StatusOK, map[string]any{
-			"message":       "Sync complete",
-			"providers":     stats,
-			"total_new":     totalNew,
-			"total_updated": totalUpdated,
-		})
+	// Provider health computed from the recorded sync runs (superusers only,
+	// as errors can reveal upstream URLs)
+	se.Router.GET("/api/venvi/providers/health", func(e *core.RequestEvent) error {
+		health, err := providers.ProvidersHealth(app)
+		if err != nil {
+			return e.InternalServerError("Failed to compute provider health", err)
+		}
+		return e.JSON(http.StatusOK, health)
+	}).Bind(apis.RequireSuperuserAuth())
+
+	// Inspect the sync lock (superusers only, as it names the host holding it)
+	se.Router.GET("/api/venvi/sync/lock", func(e *core.RequestEvent) error {
+		lock, err := providers.SyncLockStatus(app)
+		if err != nil {
+			return e.InternalServerError("Failed to read sync lock", err)
+		}
+		return e.JSON(http.StatusOK, lock)
+	}).Bind(apis.RequireSuperuserAuth())
+
+	// Sync progress with per-provider stats
+	se.Router.GET("/api/venvi/sync/{id}", func(e *core.RequestEvent) error {
+		job, ok := providers.FindSyncJob(e.Request.PathValue("id"))
+		if !ok {
+			return e.NotFoundError("Sync job not found", nil)
+		}
+		return e.JSON(http.StatusOK, job.Snapshot())
 	})
+
+	// Cancel a running sync (superusers only)
+	se.Router.POST("/api/venvi/sync/{id}/cancel", func(e *core.RequestEvent) error {
+		job, ok := providers.FindSyncJob(e.Request.PathValue("id"))
+		if !ok {
+			return e.NotFoundError("Sync job not found", nil)
+		}
+		job.Cancel()
+		return e.JSON(http.StatusAccepted, job.Snapshot())
+	}).Bind(apis.RequireSuperuserAuth())
 }
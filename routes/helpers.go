@@ -3,6 +3,7 @@ package routes
 import (
 	"strconv"
 	"venvi/providers"
+	"venvi/recommendations"
 
 	"github.com/pocketbase/pocketbase/core"
 )
@@ -35,32 +36,38 @@ func recordToEvent(r *core.Record) providers.Event {
 		SourceID:    r.GetString("source_id"),
 		Topics:      topics,
 		Category:    r.GetString("category"),
-		IsNew:       r.GetBool("is_new"),
+		FirstSeenAt: r.GetDateTime("first_seen_at").Time(),
+		LastSeenAt:  r.GetDateTime("last_seen_at").Time(),
+		Status:      r.GetString("status"),
 		Latitude:    r.GetFloat("latitude"),
 		Longitude:   r.GetFloat("longitude"),
 	}
 }
 
 // eventsToMaps converts a slice of Events to a slice of maps for JSON response.
-func eventsToMaps(events []providers.Event) []map[string]any {
+// is_new is computed for the viewer described by userCtx.
+func eventsToMaps(events []providers.Event, userCtx recommendations.UserContext) []map[string]any {
 	result := make([]map[string]any, len(events))
 	for i, e := range events {
 		result[i] = map[string]any{
-			"id":          e.ID,
-			"title":       e.Title,
-			"description": e.Description,
-			"date_start":  e.DateStart,
-			"date_end":    e.DateEnd,
-			"location":    e.Location,
-			"url":         e.URL,
-			"image_url":   e.ImageURL,
-			"source_name": e.SourceName,
-			"source_id":   e.SourceID,
-			"topics":      e.Topics,
-			"category":    e.Category,
-			"is_new":      e.IsNew,
-			"latitude":    e.Latitude,
-			"longitude":   e.Longitude,
+			"id":            e.ID,
+			"title":         e.Title,
+			"description":   e.Description,
+			"date_start":    e.DateStart,
+			"date_end":      e.DateEnd,
+			"location":      e.Location,
+			"url":           e.URL,
+			"image_url":     e.ImageURL,
+			"source_name":   e.SourceName,
+			"source_id":     e.SourceID,
+			"topics":        e.Topics,
+			"category":      e.Category,
+			"is_new":        userCtx.IsNew(&e),
+			"first_seen_at": e.FirstSeenAt,
+			"last_seen_at":  e.LastSeenAt,
+			"status":        e.Status,
+			"latitude":      e.Latitude,
+			"longitude":     e.Longitude,
 		}
 	}
 	return result
@@ -0,0 +1,54 @@
+package routes
+
+import (
+	"time"
+
+	"github.com/pocketbase/pocketbase/core"
+)
+
+// VisitTimeout is the inactivity after which a request of a user starts a
+// new visit.
+var VisitTimeout = 30 * time.Minute
+
+// visitMinInterval is how often a visit is written at most, so that pages
+// reporting it on every load do not save the user each time.
+const visitMinInterval = time.Minute
+
+// tracksVisits reports whether the user collection has the visit fields.
+func tracksVisits(user *core.Record) bool {
+	return user != nil && user.Collection().Fields.GetByName("last_active_at") != nil
+}
+
+// lastVisit returns when the authenticated user was last active before the
+// current visit, without recording anything: a request after VisitTimeout
+// starts a new visit, which recordVisit stores once the page reports it. It
+// returns the zero time for anonymous users and on a first visit.
+func lastVisit(user *core.Record) time.Time {
+	if !tracksVisits(user) {
+		return time.Time{}
+	}
+
+	lastActive := user.GetDateTime("last_active_at").Time()
+	if !lastActive.IsZero() && time.Since(lastActive) > VisitTimeout {
+		return lastActive
+	}
+	return user.GetDateTime("previous_visit_at").Time()
+}
+
+// recordVisit records that the user is active now and returns the last
+// visit as lastVisit does. The user is saved at most every
+// visitMinInterval.
+func recordVisit(app core.App, user *core.Record) (time.Time, error) {
+	if !tracksVisits(user) {
+		return time.Time{}, nil
+	}
+
+	previous := lastVisit(user)
+	if time.Since(user.GetDateTime("last_active_at").Time()) < visitMinInterval {
+		// Same visit, recorded recently enough
+		return previous, nil
+	}
+	user.Set("previous_visit_at", previous)
+	user.Set("last_active_at", time.Now())
+	return previous, app.Save(user)
+}
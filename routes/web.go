@@ -2,12 +2,14 @@
 package routes
 
 import (
+	"errors"
 	"log"
 	"net/http"
 
 	"venvi/providers"
 	"venvi/recommendations"
 
+	"github.com/pocketbase/pocketbase/apis"
 	"github.com/pocketbase/pocketbase/core"
 	"github.com/pocketbase/pocketbase/tools/template"
 )
@@ -50,7 +52,7 @@ func RegisterWebRoutes(se *core.ServeEvent, registry *template.Registry) {
 
 		records, err := app.FindRecordsByFilter(
 			collection,
-			"date_end >= @now", // Only future events
+			"date_end >= @now && status != 'removed'", // Only future events still listed
 			sortExpr,
 			limit,
 			0,
@@ -74,6 +76,7 @@ func RegisterWebRoutes(se *core.ServeEvent, registry *template.Registry) {
 		userCtx := recommendations.UserContext{
 			Latitude:  userLat,
 			Longitude: userLon,
+			LastVisit: lastVisit(e.Auth),
 		}
 		sortedEvents := svc.Recommend(userCtx, internalEvents)
 
@@ -96,4 +99,49 @@ func RegisterWebRoutes(se *core.ServeEvent, registry *template.Registry) {
 		}
 		return e.HTML(http.StatusOK, html)
 	})
+	// HTMX partials for background syncs. The status partial polls itself
+	// until the job has finished, then asks the event list to reload.
+	renderSyncStatus := func(e *core.RequestEvent, job *providers.SyncJob) error {
+		snapshot := job.Snapshot()
+		html, err := registry.LoadFiles(
+			"views/partials/sync_status.html",
+		).Render(map[string]any{
+			"job": snapshot,
+		})
+		if err != nil {
+			return e.InternalServerError("Template error", err)
+		}
+		if snapshot.Finished() {
+			e.Response.Header().Set("HX-Trigger", "reload")
+		}
+		return e.HTML(http.StatusOK, html)
+	}
+
+	se.Router.POST("/partials/sync", func(e *core.RequestEvent) error {
+		job, _, err := providers.StartSyncJob(e.App)
+		if errors.Is(err, providers.ErrSyncLocked) {
+			return e.HTML(http.StatusOK, `<div id="sync-status" class="mt-8 text-sm text-[var(--text-body)]">A sync is already running on another server. Please try again later.</div>`)
+		}
+		if err != nil {
+			return e.InternalServerError("Sync failed", err)
+		}
+		return renderSyncStatus(e, job)
+	})
+
+	se.Router.GET("/partials/sync/{id}", func(e *core.RequestEvent) error {
+		job, ok := providers.FindSyncJob(e.Request.PathValue("id"))
+		if !ok {
+			return e.NotFoundError("Sync job not found", nil)
+		}
+		return renderSyncStatus(e, job)
+	})
+
+	se.Router.POST("/partials/sync/{id}/cancel", func(e *core.RequestEvent) error {
+		job, ok := providers.FindSyncJob(e.Request.PathValue("id"))
+		if !ok {
+			return e.NotFoundError("Sync job not found", nil)
+		}
+		job.Cancel()
+		return renderSyncStatus(e, job)
+	}).Bind(apis.RequireSuperuserAuth())
 }
@@ -1,8 +1,13 @@
 package tests
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"net/http"
+	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
@@ -23,6 +28,10 @@ func TestIntegration(t *testing.T) {
 		t.Fatalf("failed to change directory: %v", err)
 	}
 
+	// Test servers need no politeness and retries need not wait
+	providers.HostInterval = 0
+	providers.RetryBaseDelay = time.Millisecond
+
 	// 1. Verify Schema and ID Constraints (Direct DB tests)
 	t.Run("DatabaseLogic", func(t *testing.T) {
 		testApp, err := createTestApp(t)
@@ -41,7 +50,7 @@ func TestIntegration(t *testing.T) {
 		expectedFields := []string{
 			"title", "description", "date_start", "date_end",
 			"location", "url", "image_url", "source_name",
-			"source_id", "topics", "category", "is_new",
+			"source_id", "topics", "category", "first_seen_at", "last_seen_at",
 		}
 
 		for _, fieldName := range expectedFields {
@@ -62,21 +71,992 @@ func TestIntegration(t *testing.T) {
 			Category:   "test",
 		}
 
-		record := core.NewRecord(collection)
-		record.Set("title", event.Title)
-		record.Set("date_start", event.DateStart)
-		record.Set("date_end", event.DateEnd)
-		record.Set("url", event.URL)
-		record.Set("source_name", event.SourceName)
-		record.Set("source_id", event.SourceID)
-		record.Set("category", event.Category)
+		record := core.NewRecord(collection)
+		record.Set("title", event.Title)
+		record.Set("date_start", event.DateStart)
+		record.Set("date_end", event.DateEnd)
+		record.Set("url", event.URL)
+		record.Set("source_name", event.SourceName)
+		record.Set("source_id", event.SourceID)
+		record.Set("category", event.Category)
+
+		err = testApp.Save(record)
+		require.NoError(t, err, "Should save record even if source ID is long")
+		assert.Len(t, record.Id, 15, "PocketBase should generate a 15-character ID")
+	})
+
+	// Provider records toggle and extend the compiled-in providers
+	t.Run("ProviderRegistry", func(t *testing.T) {
+		testApp, err := createTestApp(t)
+		require.NoError(t, err)
+		defer testApp.Cleanup()
+
+		collection, err := testApp.FindCollectionByNameOrId("providers")
+		require.NoError(t, err)
+
+		disabled := core.NewRecord(collection)
+		disabled.Set("type", "drinbz")
+		disabled.Set("source_name", "drinbz")
+		disabled.Set("enabled", false)
+		require.NoError(t, testApp.Save(disabled))
+
+		added := core.NewRecord(collection)
+		added.Set("type", "tribe_events")
+		added.Set("source_name", "example_venue")
+		added.Set("enabled", true)
+		added.Set("base_url", "https://venue.example.com")
+		added.Set("options", map[string]any{"location": "Bolzano"})
+		require.NoError(t, testApp.Save(added))
+
+		active, err := providers.ActiveProviders(testApp)
+		require.NoError(t, err)
+
+		names := make(map[string]bool)
+		for _, p := range active {
+			names[p.SourceName()] = true
+		}
+		assert.False(t, names["drinbz"], "disabled provider should not be active")
+		assert.True(t, names["odh"], "providers without a record stay active")
+		assert.True(t, names["example_venue"], "enabled records add providers")
+	})
+
+	// Each active provider gets its own cron job
+	t.Run("SyncSchedules", func(t *testing.T) {
+		testApp, err := createTestApp(t)
+		require.NoError(t, err)
+		defer testApp.Cleanup()
+
+		collection, err := testApp.FindCollectionByNameOrId("providers")
+		require.NoError(t, err)
+
+		configs := []map[string]any{
+			{"type": "odh", "source_name": "odh", "enabled": true, "schedule": "*/15 * * * *"},
+			{"type": "noi", "source_name": "noi", "enabled": true, "schedule": "not a cron"},
+			{"type": "drinbz", "source_name": "drinbz", "enabled": false},
+		}
+		records := make([]*core.Record, len(configs))
+		for i, cfg := range configs {
+			records[i] = core.NewRecord(collection)
+			records[i].Load(cfg)
+			require.NoError(t, testApp.Save(records[i]))
+		}
+
+		require.NoError(t, providers.RegisterSyncJobs(testApp))
+
+		jobs := make(map[string]string)
+		for _, job := range testApp.Cron().Jobs() {
+			jobs[job.Id()] = job.Expression()
+		}
+		assert.Equal(t, "*/15 * * * *", jobs["sync_odh"])
+		assert.Equal(t, providers.DefaultSyncSchedule, jobs["sync_noi"], "invalid schedules fall back to the default")
+		assert.Equal(t, providers.DefaultSyncSchedule, jobs["sync_euro_hackathons"])
+		assert.NotContains(t, jobs, "sync_drinbz")
+
+		// Disabling a provider removes its job on the next registration
+		records[0].Set("enabled", false)
+		require.NoError(t, testApp.Save(records[0]))
+		require.NoError(t, providers.RegisterSyncJobs(testApp))
+
+		jobs = make(map[string]string)
+		for _, job := range testApp.Cron().Jobs() {
+			jobs[job.Id()] = job.Expression()
+		}
+		assert.NotContains(t, jobs, "sync_odh")
+		assert.Contains(t, jobs, "sync_noi")
+	})
+
+	// Cron jobs firing together queue behind each other instead of being dropped
+	t.Run("ConcurrentSchedules", func(t *testing.T) {
+		testApp, err := createTestApp(t)
+		require.NoError(t, err)
+		defer testApp.Cleanup()
+
+		release := make(chan struct{})
+		server := func(blocking bool) *httptest.Server {
+			return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if blocking {
+					select {
+					case <-release:
+					case <-r.Context().Done():
+					}
+				}
+				_, _ = w.Write([]byte(`{"events": [{
+					"id": 1, "title": "Scheduled event", "url": "https://example.com/1",
+					"utc_start_date": "2030-01-01 18:00:00", "utc_end_date": "2030-01-01 20:00:00"
+				}]}`))
+			}))
+		}
+		first := server(true)
+		defer first.Close()
+		second := server(false)
+		defer second.Close()
+
+		defaults := providers.Providers
+		providers.Providers = nil
+		defer func() { providers.Providers = defaults }()
+
+		collection, err := testApp.FindCollectionByNameOrId("providers")
+		require.NoError(t, err)
+		for _, cfg := range []map[string]any{
+			{"type": "tribe_events", "source_name": "first", "enabled": true, "base_url": first.URL},
+			{"type": "tribe_events", "source_name": "second", "enabled": true, "base_url": second.URL},
+		} {
+			record := core.NewRecord(collection)
+			record.Load(cfg)
+			require.NoError(t, testApp.Save(record))
+		}
+		require.NoError(t, providers.RegisterSyncJobs(testApp))
+
+		cronJobs := make(map[string]func())
+		for _, job := range testApp.Cron().Jobs() {
+			cronJobs[job.Id()] = job.Run
+		}
+		require.Contains(t, cronJobs, "sync_first")
+		require.Contains(t, cronJobs, "sync_second")
+
+		ran := make(chan struct{}, 2)
+		go func() { cronJobs["sync_first"](); ran <- struct{}{} }()
+		require.Eventually(t, func() bool {
+			_, ok := providers.RunningSyncJob()
+			return ok
+		}, 3*time.Second, 10*time.Millisecond)
+		go func() { cronJobs["sync_second"](); ran <- struct{}{} }()
+
+		// A manual sync does not join the single-provider job but the queue
+		var queued *providers.SyncJob
+		require.Eventually(t, func() bool {
+			job, joined, err := providers.StartSyncJob(testApp)
+			if err != nil || !joined {
+				return false
+			}
+			queued = job
+			return job.Snapshot().Status == providers.SyncJobQueued
+		}, 3*time.Second, 10*time.Millisecond)
+		assert.Len(t, queued.Snapshot().Providers, 2)
+
+		close(release)
+		for range 2 {
+			select {
+			case <-ran:
+			case <-time.After(5 * time.Second):
+				t.Fatal("scheduled sync did not finish")
+			}
+		}
+		<-queued.Done()
+
+		for _, source := range []string{"first", "second"} {
+			records, err := testApp.FindRecordsByFilter("events", "source_name = {:source}", "", 0, 0, map[string]any{"source": source})
+			require.NoError(t, err)
+			assert.Len(t, records, 1, "events of %s", source)
+		}
+		assert.Equal(t, providers.SyncJobCompleted, queued.Snapshot().Status)
+		for _, p := range queued.Snapshot().Providers {
+			assert.Equal(t, providers.ProviderDone, p.State, p.Stats.Provider)
+		}
+	})
+
+	// Providers are fetched concurrently and a slow one only times out itself
+	t.Run("ConcurrentSync", func(t *testing.T) {
+		testApp, err := createTestApp(t)
+		require.NoError(t, err)
+		defer testApp.Cleanup()
+
+		fast := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte(`{"events": [{
+				"id": 1, "title": "Fast event", "url": "https://fast.example.com/1",
+				"utc_start_date": "2030-01-01 18:00:00", "utc_end_date": "2030-01-01 20:00:00"
+			}]}`))
+		}))
+		defer fast.Close()
+
+		slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			select {
+			case <-r.Context().Done():
+			case <-time.After(5 * time.Second):
+			}
+			_, _ = w.Write([]byte(`{"events": []}`))
+		}))
+		defer slow.Close()
+
+		// Only run the configured test providers
+		defaults := providers.Providers
+		providers.Providers = nil
+		defer func() { providers.Providers = defaults }()
+
+		collection, err := testApp.FindCollectionByNameOrId("providers")
+		require.NoError(t, err)
+		for _, cfg := range []map[string]any{
+			{"type": "tribe_events", "source_name": "fast", "enabled": true, "base_url": fast.URL},
+			{"type": "tribe_events", "source_name": "slow", "enabled": true, "base_url": slow.URL, "timeout": 1},
+		} {
+			record := core.NewRecord(collection)
+			record.Load(cfg)
+			require.NoError(t, testApp.Save(record))
+		}
+
+		stats, err := providers.SyncAllEvents(testApp)
+		require.NoError(t, err)
+
+		assert.Equal(t, 1, stats["fast"].New)
+		assert.Equal(t, 0, stats["fast"].Timeouts)
+		assert.Equal(t, 1, stats["slow"].Timeouts)
+		assert.Equal(t, 0, stats["slow"].Errors, "timeouts are reported separately from errors")
+
+		// Every provider run is recorded and feeds the health report
+		runs, err := testApp.FindAllRecords("sync_runs")
+		require.NoError(t, err)
+		assert.Len(t, runs, 2)
+
+		health, err := providers.ProvidersHealth(testApp)
+		require.NoError(t, err)
+		require.Len(t, health, 2)
+		byName := map[string]providers.ProviderHealth{}
+		for _, h := range health {
+			byName[h.Provider] = h
+		}
+		assert.Equal(t, providers.HealthOK, byName["fast"].Status)
+		assert.Equal(t, 1, byName["fast"].LastStats.Fetched)
+		assert.Equal(t, 1, byName["fast"].LastStats.Mapped)
+		assert.Equal(t, providers.HealthFailing, byName["slow"].Status)
+		assert.Equal(t, 1, byName["slow"].ConsecutiveFailures)
+		assert.Nil(t, byName["slow"].LastSuccessAt)
+		assert.NotEmpty(t, byName["slow"].LastErrors)
+	})
+
+	// Fetched payloads are archived and can be re-mapped without the network
+	t.Run("RemapArchivedEvents", func(t *testing.T) {
+		testApp, err := createTestApp(t)
+		require.NoError(t, err)
+		defer testApp.Cleanup()
+
+		requests := 0
+		source := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			requests++
+			_, _ = w.Write([]byte(`{"events": [{
+				"id": 7, "title": "Archived event", "url": "https://venue.example.com/7",
+				"utc_start_date": "2030-01-01 18:00:00", "utc_end_date": "2030-01-01 20:00:00"
+			}]}`))
+		}))
+		defer source.Close()
+
+		defaults := providers.Providers
+		providers.Providers = []providers.EventProvider{providers.NewTribeEventsProvider("venue", source.URL)}
+		defer func() { providers.Providers = defaults }()
+
+		_, err = providers.SyncAllEvents(testApp)
+		require.NoError(t, err)
+		fetches := requests
+
+		archived, fetchedAt, err := providers.LatestRawEvents(testApp, "venue")
+		require.NoError(t, err)
+		assert.Len(t, archived, 1)
+		assert.False(t, fetchedAt.IsZero())
+
+		// Lose the mapped event, then restore it from the archive
+		events, err := testApp.FindAllRecords("events")
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+		sourceID := events[0].GetString("source_id")
+		require.NoError(t, testApp.Delete(events[0]))
+
+		stats, err := providers.RemapEvents(testApp, "venue")
+		require.NoError(t, err)
+		assert.Equal(t, 1, stats["venue"].New)
+		assert.Equal(t, fetches, requests, "remap must not fetch")
+
+		events, err = testApp.FindAllRecords("events")
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+		assert.Equal(t, sourceID, events[0].GetString("source_id"))
+
+		_, err = providers.RemapEvents(testApp, "unknown")
+		assert.Error(t, err)
+	})
+
+	// Unchanged sources answer 304 and their events are not written again
+	t.Run("ConditionalRequests", func(t *testing.T) {
+		testApp, err := createTestApp(t)
+		require.NoError(t, err)
+		defer testApp.Cleanup()
+
+		etag := `"v1"`
+		title := "Cached event"
+		source := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("If-None-Match") == etag {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.Header().Set("ETag", etag)
+			_, _ = fmt.Fprintf(w, `{"events": [{
+				"id": 9, "title": %q, "url": "https://venue.example.com/9",
+				"utc_start_date": "2030-01-01 18:00:00", "utc_end_date": "2030-01-01 20:00:00"
+			}]}`, title)
+		}))
+		defer source.Close()
+
+		defaults := providers.Providers
+		providers.Providers = []providers.EventProvider{providers.NewTribeEventsProvider("venue", source.URL)}
+		defer func() { providers.Providers = defaults }()
+
+		stats, err := providers.SyncAllEvents(testApp)
+		require.NoError(t, err)
+		assert.Equal(t, 1, stats["venue"].New)
+		assert.False(t, stats["venue"].NotModified)
+
+		created, err := testApp.FindFirstRecordByFilter("events", "source_id = '9'")
+		require.NoError(t, err)
+		firstSeen := created.GetDateTime("first_seen_at")
+		assert.False(t, firstSeen.IsZero())
+
+		stats, err = providers.SyncAllEvents(testApp)
+		require.NoError(t, err)
+		assert.True(t, stats["venue"].NotModified)
+		assert.Equal(t, 0, stats["venue"].New+stats["venue"].Updated)
+
+		// A changed source is fetched and written in full again
+		etag, title = `"v2"`, "Renamed event"
+		stats, err = providers.SyncAllEvents(testApp)
+		require.NoError(t, err)
+		assert.False(t, stats["venue"].NotModified)
+		assert.Equal(t, 1, stats["venue"].Updated)
+
+		events, err := testApp.FindAllRecords("events")
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+		assert.Equal(t, "Renamed event", events[0].GetString("title"))
+		assert.Equal(t, firstSeen, events[0].GetDateTime("first_seen_at"), "first_seen_at is kept")
+		assert.True(t, events[0].GetDateTime("last_seen_at").After(firstSeen))
+
+		// The rename is recorded
+		history, err := providers.EventHistory(testApp, events[0].Id)
+		require.NoError(t, err)
+		require.Len(t, history, 1)
+		assert.Equal(t, "venue", history[0].Provider)
+		assert.Equal(t, providers.FieldChange{Old: "Cached event", New: "Renamed event"}, history[0].Changes["title"])
+		assert.Len(t, history[0].Changes, 1)
+
+		// A sync that failed to save an event keeps the old cache entry, so
+		// the next one fetches the event again instead of getting a 304
+		failing := true
+		testApp.OnRecordUpdate("events").BindFunc(func(e *core.RecordEvent) error {
+			if failing {
+				return errors.New("disk full")
+			}
+			return e.Next()
+		})
+		etag, title = `"v3"`, "Retried event"
+		stats, err = providers.SyncAllEvents(testApp)
+		require.NoError(t, err)
+		assert.Equal(t, 1, stats["venue"].Errors)
+
+		failing = false
+		stats, err = providers.SyncAllEvents(testApp)
+		require.NoError(t, err)
+		assert.False(t, stats["venue"].NotModified)
+		assert.Equal(t, 1, stats["venue"].Updated)
+
+		retried, err := testApp.FindRecordById("events", events[0].Id)
+		require.NoError(t, err)
+		assert.Equal(t, "Retried event", retried.GetString("title"))
+	})
+
+	// Incremental providers fetch changes since the last sync, unless a full
+	// sync is requested
+	t.Run("IncrementalSync", func(t *testing.T) {
+		testApp, err := createTestApp(t)
+		require.NoError(t, err)
+		defer testApp.Cleanup()
+
+		var updatedFrom []string
+		source := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			updatedFrom = append(updatedFrom, r.URL.Query().Get("updatefrom"))
+			if r.URL.Query().Get("updatefrom") == "" {
+				_, _ = w.Write([]byte(`{"TotalResults": 1, "Items": [{"Id": "full"}]}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"TotalResults": 0, "Items": []}`))
+		}))
+		defer source.Close()
+
+		odh := providers.NewODHProvider()
+		odh.BaseURL = source.URL
+		defaults := providers.Providers
+		providers.Providers = []providers.EventProvider{odh}
+		defer func() { providers.Providers = defaults }()
+
+		stats, err := providers.SyncAllEvents(testApp)
+		require.NoError(t, err)
+		assert.False(t, stats["odh"].Incremental)
+
+		stats, err = providers.SyncAllEvents(testApp)
+		require.NoError(t, err)
+		assert.True(t, stats["odh"].Incremental)
+
+		// Remapping uses the full fetch, not the changes since
+		archived, _, err := providers.LatestRawEvents(testApp, "odh")
+		require.NoError(t, err)
+		assert.Len(t, archived, 1)
+
+		job, _, err := providers.StartFullSyncJob(testApp)
+		require.NoError(t, err)
+		stats = job.Wait()
+		assert.False(t, stats["odh"].Incremental)
+
+		today := time.Now().UTC().Format("2006-01-02")
+		assert.Equal(t, []string{"", today, ""}, updatedFrom)
+	})
+
+	// Events a complete fetch no longer contains are removed after the grace
+	// period; failed fetches remove nothing
+	t.Run("ReconcileRemovedEvents", func(t *testing.T) {
+		testApp, err := createTestApp(t)
+		require.NoError(t, err)
+		defer testApp.Cleanup()
+
+		listed := []int{1, 2, 3}
+		failing := false
+		source := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			if failing {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			var items []string
+			for _, id := range listed {
+				items = append(items, fmt.Sprintf(`{
+					"id": %d, "title": "Event %d", "url": "https://venue.example.com/%d",
+					"utc_start_date": "2030-01-01 18:00:00", "utc_end_date": "2030-01-01 20:00:00"
+				}`, id, id, id))
+			}
+			_, _ = fmt.Fprintf(w, `{"events": [%s]}`, strings.Join(items, ","))
+		}))
+		defer source.Close()
+
+		defaults, grace, retries := providers.Providers, providers.RemovalGrace, providers.MaxRetries
+		providers.Providers = []providers.EventProvider{providers.NewTribeEventsProvider("venue", source.URL)}
+		providers.MaxRetries = 0
+		defer func() {
+			providers.Providers, providers.RemovalGrace, providers.MaxRetries = defaults, grace, retries
+		}()
+
+		status := func(sourceID string) string {
+			record, err := testApp.FindFirstRecordByFilter("events", "source_id = {:id}", map[string]any{"id": sourceID})
+			require.NoError(t, err)
+			return record.GetString("status")
+		}
+
+		_, err = providers.SyncAllEvents(testApp)
+		require.NoError(t, err)
+
+		// Within the grace period a missing event stays listed. Events fetched
+		// again as they were are not written.
+		listed = []int{1, 2}
+		stats, err := providers.SyncAllEvents(testApp)
+		require.NoError(t, err)
+		assert.Equal(t, 0, stats["venue"].Removed)
+		assert.Equal(t, 2, stats["venue"].Unchanged)
+		assert.Equal(t, 0, stats["venue"].Updated)
+		assert.Equal(t, providers.EventScheduled, status("3"))
+
+		providers.RemovalGrace = 0
+		failing = true
+		stats, err = providers.SyncAllEvents(testApp)
+		require.NoError(t, err)
+		assert.Equal(t, 0, stats["venue"].Removed)
+		assert.Equal(t, providers.EventScheduled, status("3"))
+
+		failing = false
+		stats, err = providers.SyncAllEvents(testApp)
+		require.NoError(t, err)
+		assert.Equal(t, 1, stats["venue"].Removed)
+		assert.Equal(t, providers.EventRemoved, status("3"))
+		assert.Equal(t, providers.EventScheduled, status("1"))
+
+		removed, err := testApp.FindFirstRecordByFilter("events", "source_id = '3'")
+		require.NoError(t, err)
+		history, err := providers.EventHistory(testApp, removed.Id)
+		require.NoError(t, err)
+		require.Len(t, history, 1)
+		assert.Equal(t, providers.FieldChange{Old: providers.EventScheduled, New: providers.EventRemoved}, history[0].Changes["status"])
+
+		// A fetch missing most events is not trusted
+		listed = nil
+		stats, err = providers.SyncAllEvents(testApp)
+		require.NoError(t, err)
+		assert.Equal(t, 0, stats["venue"].Removed)
+		assert.Equal(t, providers.EventScheduled, status("1"))
+
+		// A removed event listed again is restored
+		listed = []int{1, 2, 3}
+		_, err = providers.SyncAllEvents(testApp)
+		require.NoError(t, err)
+		assert.Equal(t, providers.EventScheduled, status("3"))
+
+		// An event never seen since last_seen_at was recorded is kept, as
+		// it is not known how long it has been missing
+		_, err = testApp.DB().NewQuery("UPDATE events SET last_seen_at = '' WHERE source_id = '3'").Execute()
+		require.NoError(t, err)
+		listed = []int{1, 2}
+		stats, err = providers.SyncAllEvents(testApp)
+		require.NoError(t, err)
+		assert.Equal(t, 0, stats["venue"].Removed)
+		assert.Equal(t, providers.EventScheduled, status("3"))
+	})
+
+	// Fetches that missed part of their source remove nothing
+	t.Run("PartialFetchesAreNotReconciled", func(t *testing.T) {
+		testApp, err := createTestApp(t)
+		require.NoError(t, err)
+		defer testApp.Cleanup()
+
+		failing := false
+		calendar := func(uid string) *httptest.Server {
+			return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				if failing && uid == "b" {
+					w.WriteHeader(http.StatusInternalServerError)
+					return
+				}
+				_, _ = fmt.Fprintf(w, "BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nUID:%s\r\nSUMMARY:Event %s\r\n"+
+					"DTSTART:20300101T180000Z\r\nDTEND:20300101T200000Z\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n", uid, uid)
+			}))
+		}
+		feedA, feedB := calendar("a"), calendar("b")
+		defer feedA.Close()
+		defer feedB.Close()
+
+		listing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			page := r.URL.Query().Get("page")
+			next := ""
+			if page == "" {
+				page = "1"
+				next = "http://" + r.Host + "/?page=2"
+			}
+			_, _ = fmt.Fprintf(w, `{"events": [{
+				"id": %s, "title": "Page %s", "url": "https://venue.example.com/%s",
+				"utc_start_date": "2030-01-01 18:00:00", "utc_end_date": "2030-01-01 20:00:00"
+			}], "next_rest_url": %q}`, page, page, page, next)
+		}))
+		defer listing.Close()
+		venue := providers.NewTribeEventsProvider("venue", listing.URL)
+
+		defaults, grace, retries := providers.Providers, providers.RemovalGrace, providers.MaxRetries
+		providers.Providers = []providers.EventProvider{venue}
+		providers.MaxRetries = 0
+		defer func() {
+			providers.Providers, providers.RemovalGrace, providers.MaxRetries = defaults, grace, retries
+		}()
+
+		collection, err := testApp.FindCollectionByNameOrId("providers")
+		require.NoError(t, err)
+		record := core.NewRecord(collection)
+		record.Load(map[string]any{
+			"type": "ics", "source_name": "calendars", "enabled": true,
+			"options": map[string]any{"feeds": []map[string]any{{"url": feedA.URL}, {"url": feedB.URL}}},
+		})
+		require.NoError(t, testApp.Save(record))
+
+		stats, err := providers.SyncAllEvents(testApp)
+		require.NoError(t, err)
+		assert.Equal(t, 2, stats["calendars"].New)
+		assert.Equal(t, 2, stats["venue"].New)
+		assert.False(t, stats["calendars"].Partial)
+		assert.False(t, stats["venue"].Partial)
+
+		lastSeen := func(title string) types.DateTime {
+			record, err := testApp.FindFirstRecordByFilter("events", "title = {:title}", map[string]any{"title": title})
+			require.NoError(t, err)
+			return record.GetDateTime("last_seen_at")
+		}
+		seenBefore := lastSeen("Event a")
+
+		// One feed fails and pagination is capped before the second page
+		providers.RemovalGrace = 0
+		failing = true
+		venue.MaxPages = 1
+		stats, err = providers.SyncAllEvents(testApp)
+		require.NoError(t, err)
+		assert.True(t, stats["calendars"].Partial)
+		assert.True(t, stats["venue"].Partial)
+		assert.Equal(t, 0, stats["calendars"].Removed)
+		assert.Equal(t, 0, stats["venue"].Removed)
+
+		// Events the partial fetch did contain were still seen
+		assert.Equal(t, 1, stats["calendars"].Unchanged)
+		assert.True(t, lastSeen("Event a").After(seenBefore))
+
+		removed, err := testApp.FindRecordsByFilter("events", "status = 'removed'", "", 0, 0)
+		require.NoError(t, err)
+		assert.Empty(t, removed)
+
+		// Runs record that they were partial
+		partialRuns, err := testApp.FindRecordsByFilter("sync_runs", "partial = true", "", 0, 0)
+		require.NoError(t, err)
+		assert.Len(t, partialRuns, 2)
+	})
+
+	// Date shifts between syncs and cancellation keywords set the status
+	t.Run("LifecycleStatus", func(t *testing.T) {
+		testApp, err := createTestApp(t)
+		require.NoError(t, err)
+		defer testApp.Cleanup()
+
+		start, title := "2030-01-01 18:00:00", "Hackathon"
+		source := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = fmt.Fprintf(w, `{"events": [{
+				"id": 5, "title": %q, "url": "https://venue.example.com/5",
+				"utc_start_date": %q, "utc_end_date": "2030-01-03 20:00:00"
+			}]}`, title, start)
+		}))
+		defer source.Close()
+
+		defaults := providers.Providers
+		providers.Providers = []providers.EventProvider{providers.NewTribeEventsProvider("venue", source.URL)}
+		defer func() { providers.Providers = defaults }()
+
+		status := func() string {
+			record, err := testApp.FindFirstRecordByFilter("events", "source_id = '5'")
+			require.NoError(t, err)
+			return record.GetString("status")
+		}
+
+		_, err = providers.SyncAllEvents(testApp)
+		require.NoError(t, err)
+		assert.Equal(t, providers.EventScheduled, status())
+
+		start = "2030-01-02 18:00:00"
+		_, err = providers.SyncAllEvents(testApp)
+		require.NoError(t, err)
+		assert.Equal(t, providers.EventRescheduled, status())
+
+		title = "ABGESAGT: Hackathon"
+		_, err = providers.SyncAllEvents(testApp)
+		require.NoError(t, err)
+		assert.Equal(t, providers.EventCancelled, status())
+	})
+
+	// Streaming providers keep the batches written before a late failure
+	t.Run("StreamingSync", func(t *testing.T) {
+		testApp, err := createTestApp(t)
+		require.NoError(t, err)
+		defer testApp.Cleanup()
+
+		defaults := providers.Providers
+		providers.Providers = []providers.EventProvider{&streamingProvider{
+			batches: [][]providers.RawEvent{
+				{{"id": "1", "title": "First"}, {"id": "2", "title": "Second"}},
+				{{"id": "3", "title": "Third"}},
+			},
+			err: errors.New("upstream went away"),
+		}}
+		defer func() { providers.Providers = defaults }()
+
+		stats, err := providers.SyncAllEvents(testApp)
+		require.NoError(t, err)
+		assert.Equal(t, 3, stats["stream"].Fetched)
+		assert.Equal(t, 3, stats["stream"].New)
+		assert.Equal(t, 1, stats["stream"].Errors)
+
+		events, err := testApp.FindAllRecords("events")
+		require.NoError(t, err)
+		assert.Len(t, events, 3)
+
+		// Every batch is archived
+		archived, _, err := providers.LatestRawEvents(testApp, "stream")
+		require.NoError(t, err)
+		assert.Len(t, archived, 3)
+	})
+
+	// Streamed pages answered with 304 count as unchanged and were seen
+	t.Run("StreamingNotModified", func(t *testing.T) {
+		testApp, err := createTestApp(t)
+		require.NoError(t, err)
+		defer testApp.Cleanup()
+
+		source := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("If-None-Match") == `"v1"` {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.Header().Set("ETag", `"v1"`)
+			_, _ = w.Write([]byte(`{"TotalResults": 1, "Items": [{
+				"Id": "streamed", "DateBegin": "2030-01-01T18:00:00", "DateEnd": "2030-01-01T20:00:00",
+				"Detail": {"en": {"Title": "Streamed event", "BaseText": "Long enough to be kept"}}
+			}]}`))
+		}))
+		defer source.Close()
+
+		odh := providers.NewODHProvider()
+		odh.BaseURL = source.URL
+		defaults := providers.Providers
+		providers.Providers = []providers.EventProvider{odh}
+		defer func() { providers.Providers = defaults }()
+
+		stats, err := providers.SyncAllEvents(testApp)
+		require.NoError(t, err)
+		require.Equal(t, 1, stats["odh"].New)
+		event, err := testApp.FindFirstRecordByFilter("events", "title = 'Streamed event'")
+		require.NoError(t, err)
+
+		// A full sync asks for the same page again
+		job, _, err := providers.StartFullSyncJob(testApp)
+		require.NoError(t, err)
+		stats = job.Wait()
+		assert.True(t, stats["odh"].NotModified)
+		assert.Equal(t, 1, stats["odh"].Unchanged)
+
+		refreshed, err := testApp.FindRecordById("events", event.Id)
+		require.NoError(t, err)
+		assert.True(t, refreshed.GetDateTime("last_seen_at").After(event.GetDateTime("last_seen_at")))
+	})
+
+	// A run mapping nothing after a healthy history is marked degraded
+	t.Run("BreakageDetection", func(t *testing.T) {
+		testApp, err := createTestApp(t)
+		require.NoError(t, err)
+		defer testApp.Cleanup()
+
+		empty := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte(`{"events": []}`))
+		}))
+		defer empty.Close()
+
+		defaults := providers.Providers
+		providers.Providers = []providers.EventProvider{providers.NewTribeEventsProvider("venue", empty.URL)}
+		defer func() { providers.Providers = defaults }()
+
+		collection, err := testApp.FindCollectionByNameOrId("sync_runs")
+		require.NoError(t, err)
+		for i := 1; i <= 3; i++ {
+			record := core.NewRecord(collection)
+			record.Set("provider", "venue")
+			record.Set("state", "done")
+			record.Set("started_at", time.Now().Add(-time.Duration(i)*6*time.Hour))
+			record.Set("fetched", 20)
+			record.Set("mapped", 20)
+			require.NoError(t, testApp.Save(record))
+		}
+
+		stats, err := providers.SyncAllEvents(testApp)
+		require.NoError(t, err)
+		assert.Equal(t, 0, stats["venue"].Errors)
+		assert.NotEmpty(t, stats["venue"].Warnings)
+
+		health, err := providers.ProvidersHealth(testApp)
+		require.NoError(t, err)
+		require.Len(t, health, 1)
+		assert.Equal(t, providers.HealthDegraded, health[0].Status)
+		assert.Equal(t, providers.ProviderDegraded, health[0].LastRunState)
+		assert.NotEmpty(t, health[0].LastWarnings)
+	})
+
+	// A source that shrank for good becomes the new baseline
+	t.Run("BreakageRelearning", func(t *testing.T) {
+		testApp, err := createTestApp(t)
+		require.NoError(t, err)
+		defer testApp.Cleanup()
+
+		requests := 0
+		shrunk := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			requests++
+			var events []string
+			for i := 1; i <= 4; i++ {
+				events = append(events, fmt.Sprintf(`{"id": %d, "title": "Event %d (%d)", "url": "https://example.com/%d",
+					"utc_start_date": "2030-01-01 18:00:00", "utc_end_date": "2030-01-01 20:00:00"}`, i, i, requests, i))
+			}
+			_, _ = w.Write([]byte(`{"events": [` + strings.Join(events, ",") + `]}`))
+		}))
+		defer shrunk.Close()
+
+		defaults := providers.Providers
+		providers.Providers = []providers.EventProvider{providers.NewTribeEventsProvider("venue", shrunk.URL)}
+		defer func() { providers.Providers = defaults }()
+
+		collection, err := testApp.FindCollectionByNameOrId("sync_runs")
+		require.NoError(t, err)
+		for i, state := range []string{"degraded", "degraded", "done", "done", "done"} {
+			mapped := 20
+			if state == "degraded" {
+				mapped = 4
+			}
+			record := core.NewRecord(collection)
+			record.Set("provider", "venue")
+			record.Set("state", state)
+			record.Set("started_at", time.Now().Add(-time.Duration(i+1)*6*time.Hour))
+			record.Set("fetched", mapped)
+			record.Set("mapped", mapped)
+			require.NoError(t, testApp.Save(record))
+		}
+
+		// Two degraded runs are not enough to accept the new level
+		stats, err := providers.SyncAllEvents(testApp)
+		require.NoError(t, err)
+		assert.Equal(t, 4, stats["venue"].Mapped)
+		assert.NotEmpty(t, stats["venue"].Warnings)
+
+		// After the third the source is judged against it, also once healthy
+		// runs at the new level follow
+		for i := 0; i < 4; i++ {
+			stats, err = providers.SyncAllEvents(testApp)
+			require.NoError(t, err)
+			assert.Empty(t, stats["venue"].Warnings, "run %d after relearning", i+1)
+		}
+
+		health, err := providers.ProvidersHealth(testApp)
+		require.NoError(t, err)
+		require.Len(t, health, 1)
+		assert.Equal(t, providers.HealthOK, health[0].Status)
+	})
+
+	// Health counts failures since the last success and flags stale providers
+	t.Run("ProviderHealth", func(t *testing.T) {
+		testApp, err := createTestApp(t)
+		require.NoError(t, err)
+		defer testApp.Cleanup()
+
+		defaults := providers.Providers
+		providers.Providers = []providers.EventProvider{providers.NewODHProvider(), providers.NewNOIProvider()}
+		defer func() { providers.Providers = defaults }()
+
+		collection, err := testApp.FindCollectionByNameOrId("sync_runs")
+		require.NoError(t, err)
+		now := time.Now()
+		for _, run := range []struct {
+			provider, state string
+			age             time.Duration
+		}{
+			{"odh", "done", 5 * time.Hour},
+			{"odh", "failed", 3 * time.Hour},
+			{"odh", "cancelled", 2 * time.Hour},
+			{"odh", "timeout", 1 * time.Hour},
+			{"noi", "done", 48 * time.Hour},
+		} {
+			record := core.NewRecord(collection)
+			record.Set("provider", run.provider)
+			record.Set("state", run.state)
+			record.Set("started_at", now.Add(-run.age))
+			record.Set("finished_at", now.Add(-run.age))
+			require.NoError(t, testApp.Save(record))
+		}
+
+		health, err := providers.ProvidersHealth(testApp)
+		require.NoError(t, err)
+		require.Len(t, health, 2)
+
+		assert.Equal(t, providers.HealthFailing, health[0].Status)
+		assert.Equal(t, 2, health[0].ConsecutiveFailures, "cancelled runs are ignored")
+		assert.Equal(t, "timeout", health[0].LastRunState)
+		assert.False(t, health[0].Stale)
+
+		assert.Equal(t, providers.HealthStale, health[1].Status)
+		assert.True(t, health[1].Stale)
+		assert.Equal(t, 0, health[1].ConsecutiveFailures)
+	})
+
+	// Syncs run in the background and can be cancelled
+	t.Run("SyncJobs", func(t *testing.T) {
+		testApp, err := createTestApp(t)
+		require.NoError(t, err)
+		defer testApp.Cleanup()
+
+		slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			select {
+			case <-r.Context().Done():
+			case <-time.After(5 * time.Second):
+			}
+			_, _ = w.Write([]byte(`{"events": []}`))
+		}))
+		defer slow.Close()
+
+		defaults := providers.Providers
+		providers.Providers = []providers.EventProvider{providers.NewTribeEventsProvider("slow", slow.URL)}
+		defer func() { providers.Providers = defaults }()
+
+		job, joined, err := providers.StartSyncJob(testApp)
+		require.NoError(t, err)
+		assert.False(t, joined)
+
+		snapshot := job.Snapshot()
+		assert.Equal(t, providers.SyncJobRunning, snapshot.Status)
+		require.Len(t, snapshot.Providers, 1)
+
+		// A second sync joins the running one
+		second, joined, err := providers.StartSyncJob(testApp)
+		require.NoError(t, err)
+		assert.True(t, joined)
+		assert.Same(t, job, second)
+
+		lock, err := providers.SyncLockStatus(testApp)
+		require.NoError(t, err)
+		assert.True(t, lock.Locked)
+		assert.Equal(t, job.ID, lock.JobID)
+
+		found, ok := providers.FindSyncJob(job.ID)
+		require.True(t, ok)
+		assert.Same(t, job, found)
+
+		job.Cancel()
+		select {
+		case <-job.Done():
+		case <-time.After(3 * time.Second):
+			t.Fatal("cancelled sync did not finish")
+		}
+
+		snapshot = job.Snapshot()
+		assert.Equal(t, providers.SyncJobCancelled, snapshot.Status)
+		assert.NotNil(t, snapshot.FinishedAt)
+		assert.Equal(t, providers.ProviderCancelled, snapshot.Providers[0].State)
+		assert.Equal(t, 0, snapshot.Total.Errors, "cancellation is not an error")
+
+		lock, err = providers.SyncLockStatus(testApp)
+		require.NoError(t, err)
+		assert.False(t, lock.Locked, "the lock is released when the job finishes")
+	})
+
+	// A lock held by another process rejects syncs until it expires
+	t.Run("SyncLock", func(t *testing.T) {
+		testApp, err := createTestApp(t)
+		require.NoError(t, err)
+		defer testApp.Cleanup()
+
+		defaults := providers.Providers
+		providers.Providers = nil
+		defer func() { providers.Providers = defaults }()
 
-		err = testApp.Save(record)
-		require.NoError(t, err, "Should save record even if source ID is long")
-		assert.Len(t, record.Id, 15, "PocketBase should generate a 15-character ID")
+		collection, err := testApp.FindCollectionByNameOrId("sync_locks")
+		require.NoError(t, err)
+		foreign := core.NewRecord(collection)
+		foreign.Set("name", "sync")
+		foreign.Set("holder", "other-host:42")
+		foreign.Set("job_id", "otherjob")
+		foreign.Set("acquired_at", time.Now())
+		foreign.Set("heartbeat_at", time.Now())
+		require.NoError(t, testApp.Save(foreign))
+
+		_, _, err = providers.StartSyncJob(testApp)
+		require.True(t, errors.Is(err, providers.ErrSyncLocked))
+		var locked *providers.SyncLockedError
+		require.True(t, errors.As(err, &locked))
+		assert.Equal(t, "other-host:42", locked.Lock.Holder)
+
+		// Without heartbeats the lock goes stale and is taken over
+		foreign.Set("heartbeat_at", time.Now().Add(-2*providers.SyncLockTTL))
+		require.NoError(t, testApp.Save(foreign))
+
+		lock, err := providers.SyncLockStatus(testApp)
+		require.NoError(t, err)
+		assert.False(t, lock.Locked)
+
+		stats, err := providers.SyncAllEvents(testApp)
+		require.NoError(t, err)
+		assert.Empty(t, stats)
 	})
 
 	// 2. Verify Routes using ApiScenario
+	defaultProviders := providers.Providers
+	// Visitors were last active two hours ago, before the current visit.
+	// Their tokens are set when the scenario's app is created.
+	visitedAt := time.Now().Add(-2 * time.Hour).Truncate(time.Millisecond)
+	readOnlyHeaders, visitHeaders := map[string]string{}, map[string]string{}
+	cancelHeaders, healthHeaders, lockHeaders := map[string]string{}, map[string]string{}, map[string]string{}
 	scenarios := []tests.ApiScenario{
 		{
 			Name:           "HealthCheck",
@@ -125,13 +1105,300 @@ func TestIntegration(t *testing.T) {
 				routes.RegisterAPIRoutes(e, app)
 			},
 		},
+		{
+			Name:               "EventsAPIHidesRemoved",
+			Method:             http.MethodGet,
+			URL:                "/api/venvi/events",
+			ExpectedStatus:     http.StatusOK,
+			ExpectedContent:    []string{`"Still listed"`},
+			NotExpectedContent: []string{`"Dropped by source"`},
+			TestAppFactory: func(t testing.TB) *tests.TestApp {
+				app, err := createTestApp(t)
+				if err != nil {
+					t.Fatalf("failed to create test app: %v", err)
+				}
+				return app
+			},
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				collection, _ := app.FindCollectionByNameOrId("events")
+				for title, status := range map[string]string{"Still listed": providers.EventScheduled, "Dropped by source": providers.EventRemoved} {
+					record := core.NewRecord(collection)
+					record.Set("title", title)
+					record.Set("date_start", time.Now().Add(24*time.Hour))
+					record.Set("date_end", time.Now().Add(26*time.Hour))
+					record.Set("url", "https://example.com/event")
+					record.Set("source_name", "test")
+					record.Set("source_id", title)
+					record.Set("category", "Tech")
+					record.Set("status", status)
+					if err := app.Save(record); err != nil {
+						t.Fatalf("failed to save event: %v", err)
+					}
+				}
+
+				routes.RegisterAPIRoutes(e, app)
+			},
+		},
+		{
+			Name:            "EventsAPIDoesNotRecordVisits",
+			Method:          http.MethodGet,
+			URL:             "/api/venvi/events",
+			Headers:         readOnlyHeaders,
+			ExpectedStatus:  http.StatusOK,
+			ExpectedContent: []string{`[`},
+			TestAppFactory: func(t testing.TB) *tests.TestApp {
+				app, err := createTestApp(t)
+				if err != nil {
+					t.Fatalf("failed to create test app: %v", err)
+				}
+				createVisitor(t, app, visitedAt, readOnlyHeaders)
+				return app
+			},
+			BeforeTestFunc: func(_ testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				routes.RegisterAPIRoutes(e, app)
+			},
+			AfterTestFunc: func(t testing.TB, app *tests.TestApp, _ *http.Response) {
+				user, err := app.FindAuthRecordByEmail("users", "visitor@example.com")
+				require.NoError(t, err)
+				assert.True(t, user.GetDateTime("last_active_at").Time().Equal(visitedAt), "listing events does not write")
+				assert.True(t, user.GetDateTime("previous_visit_at").IsZero())
+			},
+		},
+		{
+			Name:            "VisitsAPIRecordsVisit",
+			Method:          http.MethodPost,
+			URL:             "/api/venvi/visits",
+			Headers:         visitHeaders,
+			ExpectedStatus:  http.StatusOK,
+			ExpectedContent: []string{`"last_visit":"` + visitedAt.UTC().Format("2006-01-02T15:04:05")},
+			TestAppFactory: func(t testing.TB) *tests.TestApp {
+				app, err := createTestApp(t)
+				if err != nil {
+					t.Fatalf("failed to create test app: %v", err)
+				}
+				createVisitor(t, app, visitedAt, visitHeaders)
+				return app
+			},
+			BeforeTestFunc: func(_ testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				routes.RegisterAPIRoutes(e, app)
+			},
+			AfterTestFunc: func(t testing.TB, app *tests.TestApp, _ *http.Response) {
+				user, err := app.FindAuthRecordByEmail("users", "visitor@example.com")
+				require.NoError(t, err)
+				assert.True(t, user.GetDateTime("previous_visit_at").Time().Equal(visitedAt))
+				assert.WithinDuration(t, time.Now(), user.GetDateTime("last_active_at").Time(), time.Minute)
+			},
+		},
+		{
+			Name:            "VisitsAPIRequiresAuth",
+			Method:          http.MethodPost,
+			URL:             "/api/venvi/visits",
+			ExpectedStatus:  http.StatusUnauthorized,
+			ExpectedContent: []string{`"data":{}`},
+			TestAppFactory: func(t testing.TB) *tests.TestApp {
+				app, err := createTestApp(t)
+				if err != nil {
+					t.Fatalf("failed to create test app: %v", err)
+				}
+				return app
+			},
+			BeforeTestFunc: func(_ testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				routes.RegisterAPIRoutes(e, app)
+			},
+		},
+		{
+			Name:            "EventHistoryNotFound",
+			Method:          http.MethodGet,
+			URL:             "/api/venvi/events/missing/history",
+			ExpectedStatus:  http.StatusNotFound,
+			ExpectedContent: []string{`"Event not found."`},
+			TestAppFactory: func(t testing.TB) *tests.TestApp {
+				app, err := createTestApp(t)
+				if err != nil {
+					t.Fatalf("failed to create test app: %v", err)
+				}
+				return app
+			},
+			BeforeTestFunc: func(_ testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				routes.RegisterAPIRoutes(e, app)
+			},
+		},
+		{
+			Name:            "SyncJobNotFound",
+			Method:          http.MethodGet,
+			URL:             "/api/venvi/sync/missing",
+			ExpectedStatus:  http.StatusNotFound,
+			ExpectedContent: []string{`"Sync job not found."`},
+			TestAppFactory: func(t testing.TB) *tests.TestApp {
+				app, err := createTestApp(t)
+				if err != nil {
+					t.Fatalf("failed to create test app: %v", err)
+				}
+				return app
+			},
+			BeforeTestFunc: func(_ testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				routes.RegisterAPIRoutes(e, app)
+			},
+		},
+		{
+			Name:            "SyncCancelRequiresSuperuser",
+			Method:          http.MethodPost,
+			URL:             "/api/venvi/sync/missing/cancel",
+			ExpectedStatus:  http.StatusUnauthorized,
+			ExpectedContent: []string{`"data":{}`},
+			TestAppFactory: func(t testing.TB) *tests.TestApp {
+				app, err := createTestApp(t)
+				if err != nil {
+					t.Fatalf("failed to create test app: %v", err)
+				}
+				return app
+			},
+			BeforeTestFunc: func(_ testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				routes.RegisterAPIRoutes(e, app)
+			},
+		},
+		{
+			Name:            "SyncCancelPartialRequiresSuperuser",
+			Method:          http.MethodPost,
+			URL:             "/partials/sync/missing/cancel",
+			ExpectedStatus:  http.StatusUnauthorized,
+			ExpectedContent: []string{`"data":{}`},
+			TestAppFactory: func(t testing.TB) *tests.TestApp {
+				app, err := createTestApp(t)
+				if err != nil {
+					t.Fatalf("failed to create test app: %v", err)
+				}
+				return app
+			},
+			BeforeTestFunc: func(_ testing.TB, _ *tests.TestApp, e *core.ServeEvent) {
+				routes.RegisterWebRoutes(e, template.NewRegistry())
+			},
+		},
+		{
+			Name:            "SyncCancelBySuperuser",
+			Method:          http.MethodPost,
+			URL:             "/api/venvi/sync/missing/cancel",
+			Headers:         cancelHeaders,
+			ExpectedStatus:  http.StatusNotFound,
+			ExpectedContent: []string{`"Sync job not found."`},
+			TestAppFactory: func(t testing.TB) *tests.TestApp {
+				app, err := createTestApp(t)
+				if err != nil {
+					t.Fatalf("failed to create test app: %v", err)
+				}
+				createSuperuser(t, app, cancelHeaders)
+				return app
+			},
+			BeforeTestFunc: func(_ testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				routes.RegisterAPIRoutes(e, app)
+			},
+		},
+		{
+			Name:            "SyncLockAPI",
+			Method:          http.MethodGet,
+			URL:             "/api/venvi/sync/lock",
+			Headers:         lockHeaders,
+			ExpectedStatus:  http.StatusOK,
+			ExpectedContent: []string{`"locked":false`},
+			TestAppFactory: func(t testing.TB) *tests.TestApp {
+				app, err := createTestApp(t)
+				if err != nil {
+					t.Fatalf("failed to create test app: %v", err)
+				}
+				createSuperuser(t, app, lockHeaders)
+				return app
+			},
+			BeforeTestFunc: func(_ testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				routes.RegisterAPIRoutes(e, app)
+			},
+		},
+		{
+			Name:            "SyncLockAPIRequiresSuperuser",
+			Method:          http.MethodGet,
+			URL:             "/api/venvi/sync/lock",
+			ExpectedStatus:  http.StatusUnauthorized,
+			ExpectedContent: []string{`"data":{}`},
+			TestAppFactory: func(t testing.TB) *tests.TestApp {
+				app, err := createTestApp(t)
+				if err != nil {
+					t.Fatalf("failed to create test app: %v", err)
+				}
+				return app
+			},
+			BeforeTestFunc: func(_ testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				routes.RegisterAPIRoutes(e, app)
+			},
+		},
+		{
+			Name:            "SyncPartial",
+			Method:          http.MethodPost,
+			URL:             "/partials/sync",
+			ExpectedStatus:  http.StatusOK,
+			ExpectedContent: []string{`id="sync-status"`},
+			TestAppFactory: func(t testing.TB) *tests.TestApp {
+				app, err := createTestApp(t)
+				if err != nil {
+					t.Fatalf("failed to create test app: %v", err)
+				}
+				return app
+			},
+			BeforeTestFunc: func(_ testing.TB, _ *tests.TestApp, e *core.ServeEvent) {
+				// Sync no providers, so that the test stays offline
+				providers.Providers = nil
+				routes.RegisterWebRoutes(e, template.NewRegistry())
+			},
+			AfterTestFunc: func(_ testing.TB, _ *tests.TestApp, _ *http.Response) {
+				// Let the background job finish before the app is cleaned up
+				if job, ok := providers.RunningSyncJob(); ok {
+					<-job.Done()
+				}
+				providers.Providers = defaultProviders
+			},
+		},
+		{
+			Name:            "ProvidersHealthAPI",
+			Method:          http.MethodGet,
+			URL:             "/api/venvi/providers/health",
+			Headers:         healthHeaders,
+			ExpectedStatus:  http.StatusOK,
+			ExpectedContent: []string{`"provider":"odh"`, `"status":"unknown"`},
+			TestAppFactory: func(t testing.TB) *tests.TestApp {
+				app, err := createTestApp(t)
+				if err != nil {
+					t.Fatalf("failed to create test app: %v", err)
+				}
+				createSuperuser(t, app, healthHeaders)
+				return app
+			},
+			BeforeTestFunc: func(_ testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				routes.RegisterAPIRoutes(e, app)
+			},
+		},
+		{
+			Name:            "ProvidersHealthAPIRequiresSuperuser",
+			Method:          http.MethodGet,
+			URL:             "/api/venvi/providers/health",
+			ExpectedStatus:  http.StatusUnauthorized,
+			ExpectedContent: []string{`"data":{}`},
+			TestAppFactory: func(t testing.TB) *tests.TestApp {
+				app, err := createTestApp(t)
+				if err != nil {
+					t.Fatalf("failed to create test app: %v", err)
+				}
+				return app
+			},
+			BeforeTestFunc: func(_ testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				routes.RegisterAPIRoutes(e, app)
+			},
+		},
 		{
 			Name:           "WebHome",
 			Method:         http.MethodGet,
 			URL:            "/",
 			ExpectedStatus: http.StatusOK,
 			ExpectedContent: []string{
-				"<title>", // Basic check for HTML
+				"<title>",            // Basic check for HTML
+				"htmx:configRequest", // Partials get the auth token
 			},
 			TestAppFactory: func(t testing.TB) *tests.TestApp {
 				app, err := createTestApp(t)
@@ -185,7 +1452,10 @@ func createTestApp(_ testing.TB) (*tests.TestApp, error) {
 			&core.TextField{Name: "source_id", Required: true},
 			&core.JSONField{Name: "topics", Required: false},
 			&core.TextField{Name: "category", Required: true},
-			&core.BoolField{Name: "is_new", Required: false},
+			&core.TextField{Name: "status", Required: false},
+			&core.DateField{Name: "first_seen_at", Required: false},
+			&core.DateField{Name: "last_seen_at", Required: false},
+			&core.TextField{Name: "content_hash", Required: false},
 			&core.NumberField{Name: "latitude", Required: false},
 			&core.NumberField{Name: "longitude", Required: false},
 		)
@@ -196,7 +1466,207 @@ func createTestApp(_ testing.TB) (*tests.TestApp, error) {
 		if err := app.Save(collection); err != nil {
 			return nil, err
 		}
+
+		// Create 'providers' collection (runtime provider configuration)
+		providersCollection := core.NewBaseCollection("providers")
+		providersCollection.Fields.Add(
+			&core.TextField{Name: "type", Required: true},
+			&core.TextField{Name: "source_name", Required: true},
+			&core.BoolField{Name: "enabled", Required: false},
+			&core.URLField{Name: "base_url", Required: false},
+			&core.JSONField{Name: "options", Required: false},
+			&core.TextField{Name: "schedule", Required: false},
+			&core.NumberField{Name: "timeout", Required: false},
+		)
+
+		if err := app.Save(providersCollection); err != nil {
+			return nil, err
+		}
+
+		// Create 'sync_locks' collection
+		locks := core.NewBaseCollection("sync_locks")
+		locks.Fields.Add(
+			&core.TextField{Name: "name", Required: true},
+			&core.TextField{Name: "holder", Required: false},
+			&core.TextField{Name: "job_id", Required: false},
+			&core.DateField{Name: "acquired_at", Required: false},
+			&core.DateField{Name: "heartbeat_at", Required: false},
+		)
+		locks.AddIndex("idx_sync_locks_name", true, "name", "")
+
+		if err := app.Save(locks); err != nil {
+			return nil, err
+		}
+
+		// Create 'sync_runs' collection
+		runs := core.NewBaseCollection("sync_runs")
+		runs.Fields.Add(
+			&core.TextField{Name: "provider", Required: true},
+			&core.TextField{Name: "job_id", Required: false},
+			&core.TextField{Name: "state", Required: true},
+			&core.DateField{Name: "started_at", Required: true},
+			&core.DateField{Name: "finished_at", Required: false},
+			&core.NumberField{Name: "duration_ms", Required: false},
+			&core.NumberField{Name: "fetched", Required: false},
+			&core.NumberField{Name: "mapped", Required: false},
+			&core.NumberField{Name: "skipped", Required: false},
+			&core.NumberField{Name: "new", Required: false},
+			&core.NumberField{Name: "updated", Required: false},
+			&core.NumberField{Name: "errors", Required: false},
+			&core.NumberField{Name: "timeouts", Required: false},
+			&core.JSONField{Name: "error_messages", Required: false},
+			&core.JSONField{Name: "warnings", Required: false},
+			&core.BoolField{Name: "not_modified", Required: false},
+			&core.BoolField{Name: "incremental", Required: false},
+			&core.NumberField{Name: "removed", Required: false},
+			&core.BoolField{Name: "partial", Required: false},
+			&core.NumberField{Name: "unchanged", Required: false},
+		)
+
+		if err := app.Save(runs); err != nil {
+			return nil, err
+		}
+
+		// Create 'raw_events' collection
+		rawEvents := core.NewBaseCollection("raw_events")
+		rawEvents.Fields.Add(
+			&core.TextField{Name: "provider", Required: true},
+			&core.TextField{Name: "job_id", Required: false},
+			&core.DateField{Name: "fetched_at", Required: true},
+			&core.NumberField{Name: "count", Required: false},
+			&core.NumberField{Name: "page", Required: false},
+			&core.BoolField{Name: "incremental", Required: false},
+			&core.FileField{Name: "payload", Required: true, MaxSelect: 1, MaxSize: 100 << 20, Protected: true},
+		)
+
+		if err := app.Save(rawEvents); err != nil {
+			return nil, err
+		}
+
+		// Create 'http_cache' collection
+		httpCache := core.NewBaseCollection("http_cache")
+		httpCache.Fields.Add(
+			&core.TextField{Name: "url", Required: true},
+			&core.TextField{Name: "etag", Required: false},
+			&core.TextField{Name: "last_modified", Required: false},
+			&core.FileField{Name: "body", Required: true, MaxSelect: 1, MaxSize: 100 << 20, Protected: true},
+			&core.DateField{Name: "stored_at", Required: false},
+		)
+		httpCache.AddIndex("idx_http_cache_url", true, "url", "")
+
+		if err := app.Save(httpCache); err != nil {
+			return nil, err
+		}
+
+		// Create 'sync_cursors' collection
+		cursors := core.NewBaseCollection("sync_cursors")
+		cursors.Fields.Add(
+			&core.TextField{Name: "provider", Required: true},
+			&core.TextField{Name: "cursor", Required: false},
+			&core.DateField{Name: "synced_at", Required: false},
+			&core.DateField{Name: "full_sync_at", Required: false},
+		)
+		cursors.AddIndex("idx_sync_cursors_provider", true, "provider", "")
+
+		if err := app.Save(cursors); err != nil {
+			return nil, err
+		}
+
+		// Create 'event_revisions' collection
+		revisions := core.NewBaseCollection("event_revisions")
+		revisions.Fields.Add(
+			&core.RelationField{Name: "event", Required: true, CollectionId: collection.Id, CascadeDelete: true, MaxSelect: 1},
+			&core.TextField{Name: "provider", Required: false},
+			&core.DateField{Name: "changed_at", Required: true},
+			&core.JSONField{Name: "changes", Required: false},
+		)
+
+		if err := app.Save(revisions); err != nil {
+			return nil, err
+		}
+
+		// Track user visits
+		users, err := app.FindCollectionByNameOrId("users")
+		if err != nil {
+			return nil, err
+		}
+		users.Fields.Add(
+			&core.DateField{Name: "last_active_at", Required: false},
+			&core.DateField{Name: "previous_visit_at", Required: false},
+		)
+
+		if err := app.Save(users); err != nil {
+			return nil, err
+		}
 	}
 
 	return app, nil
 }
+
+// createVisitor creates a user last active at lastActive and sets the
+// Authorization header of a scenario to their token.
+func createVisitor(t testing.TB, app *tests.TestApp, lastActive time.Time, headers map[string]string) *core.Record {
+	users, err := app.FindCollectionByNameOrId("users")
+	require.NoError(t, err)
+	user := core.NewRecord(users)
+	user.SetEmail("visitor@example.com")
+	user.SetPassword("1234567890")
+	user.Set("last_active_at", lastActive)
+	require.NoError(t, app.Save(user))
+
+	token, err := user.NewAuthToken()
+	require.NoError(t, err)
+	headers["Authorization"] = token
+	return user
+}
+
+// createSuperuser creates a superuser and sets the Authorization header of a
+// scenario to its token.
+func createSuperuser(t testing.TB, app *tests.TestApp, headers map[string]string) *core.Record {
+	superusers, err := app.FindCollectionByNameOrId(core.CollectionNameSuperusers)
+	require.NoError(t, err)
+	superuser := core.NewRecord(superusers)
+	superuser.SetEmail("admin@example.com")
+	superuser.SetPassword("1234567890")
+	require.NoError(t, app.Save(superuser))
+
+	token, err := superuser.NewAuthToken()
+	require.NoError(t, err)
+	headers["Authorization"] = token
+	return superuser
+}
+
+// streamingProvider yields fixed batches, then fails with err.
+type streamingProvider struct {
+	batches [][]providers.RawEvent
+	err     error
+}
+
+func (p *streamingProvider) SourceName() string { return "stream" }
+
+func (p *streamingProvider) FetchEvents(_ context.Context) ([]providers.RawEvent, error) {
+	return nil, errors.New("streaming only")
+}
+
+func (p *streamingProvider) StreamEvents(_ context.Context, yield func([]providers.RawEvent) error) error {
+	for _, batch := range p.batches {
+		if err := yield(batch); err != nil {
+			return err
+		}
+	}
+	return p.err
+}
+
+func (p *streamingProvider) MapEvent(raw providers.RawEvent) *providers.Event {
+	start := time.Date(2030, 1, 1, 18, 0, 0, 0, time.UTC)
+	return &providers.Event{
+		Title:      raw["title"].(string),
+		DateStart:  start,
+		DateEnd:    start.Add(2 * time.Hour),
+		SourceName: p.SourceName(),
+		SourceID:   raw["id"].(string),
+		URL:        "https://stream.example.com/" + raw["id"].(string),
+		Location:   "Bolzano",
+		Category:   "Tech",
+	}
+}